@@ -2,21 +2,20 @@ package main
 
 import (
 	"avito-tech-backend/internal/core"
+	"avito-tech-backend/internal/core/jobs"
 	http_server "avito-tech-backend/internal/http-server"
 	"avito-tech-backend/internal/pkg/config"
+	"avito-tech-backend/internal/pkg/logging"
 	"context"
-	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/avast/retry-go/v4"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
 	"log"
 	"log/slog"
 	"os"
 	"time"
-
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/jackc/pgx/v4/stdlib"
 )
 
 func main() {
@@ -25,54 +24,109 @@ func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	slog.SetDefault(logger)
 
-	loader := config.PrepareLoader(config.WithConfigPath("./config.yaml"))
+	flags := newFlagSet(os.Args[0])
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	configPath, _ := flags.GetString("config")
+	printConfig, _ := flags.GetBool("print-config")
+
+	// Settings are taken from defaults, then the file, then the environment,
+	// then the flags, each overriding the previous ones.
+	loader := config.PrepareLoader(
+		config.WithConfigPath(configPath),
+		config.WithEnv(core.Config{}),
+		config.WithFlags(flags, core.Config{}),
+	)
 
 	cfg, err := core.ParseConfig(loader)
 	if err != nil {
-		log.Fatalf("Failed to parse config: %s", err)
+		fmt.Fprintf(os.Stderr, "Failed to parse config: %s\n", err)
+		os.Exit(1)
 	}
 
-	err = retry.Do(func() error {
-		return UpMigrations(cfg)
-	}, retry.Attempts(4), retry.Delay(2*time.Second))
-	if err != nil {
-		log.Fatalf(err.Error())
+	if printConfig {
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(cfg.Redacted()); err != nil {
+			log.Fatalf("Failed to print config: %s", err)
+		}
+		return
 	}
 
-	repository, err := core.NewRepository(ctx, cfg)
+	logger, err = logging.New(cfg.Log, os.Stdout)
 	if err != nil {
-		log.Fatalf("Init repository: %s", err)
+		log.Fatalf("Failed to init logger: %s", err)
 	}
-	app := http_server.New(repository)
+	slog.SetDefault(logger)
 
-	if err := app.Start(ctx); err != nil {
-		log.Fatalf(err.Error())
+	if args := flags.Args(); len(args) > 0 {
+		if args[0] != "migrate" {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n", args[0])
+			os.Exit(2)
+		}
+		if err := runMigrate(cfg, args[1:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
 	}
 
-}
-
-func UpMigrations(cfg *core.Config) error {
-	db, err := sql.Open("pgx", cfg.Storage.URL)
-	if err != nil {
-		return err
+	// Replicas started together should leave migrations to a single
+	// "migrate up" run and boot with storage.autoMigrate off.
+	if cfg.Storage.AutoMigrate {
+		err = retry.Do(func() error {
+			return upMigrations(cfg)
+		}, retry.Attempts(4), retry.Delay(2*time.Second))
+		if err != nil {
+			log.Fatalf(err.Error())
+		}
 	}
 
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	repository, err := core.NewRepository(ctx, cfg)
 	if err != nil {
-		return err
+		log.Fatalf("Init repository: %s", err)
 	}
+	purger := jobs.NewPurger(repository.Actions, cfg.Trash)
+	go purger.Run(ctx)
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://migrations",
-		"postgres", driver)
-	if err != nil {
-		return err
+	app := http_server.New(repository)
+	app.Server.AddReadinessCheck("purger", purger.Health)
+	if cfg.Cache.Warmup.Enabled || cfg.Cache.RefreshAhead > 0 {
+		warmer := jobs.NewCacheWarmer(repository.Actions, cfg.Cache)
+		go warmer.Run(ctx)
+		if cfg.Cache.Warmup.BlockReadiness {
+			app.Server.AddReadinessCheck("cache_warmup", warmer.WarmedUp)
+		}
+		if cfg.Cache.RefreshAhead > 0 {
+			app.Server.AddReadinessCheck("cache_refresh", warmer.Health)
+		}
+	}
+	if cfg.Cache.Warmup.Enabled && cfg.Cache.Warmup.TopN > 0 {
+		flusher := jobs.NewRequestFlusher(repository.Actions)
+		go flusher.Run(ctx)
+		app.Server.AddReadinessCheck("request_counts", flusher.Health)
 	}
+	go reloadOnHangup(ctx, loader, cfg, app)
 
-	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return err
+	if err := app.Start(ctx); err != nil {
+		log.Fatalf(err.Error())
 	}
 
-	return nil
+}
 
+// newFlagSet defines the global flags. Parsing stops at the first argument
+// that is not a flag, so a subcommand keeps its own arguments, such as the
+// -1 in "migrate force -1".
+func newFlagSet(name string) *pflag.FlagSet {
+	flags := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	flags.SetInterspersed(false)
+	flags.String("config", "./config.yaml", "path to the config file")
+	flags.Bool("print-config", false, "print the effective config with secrets redacted and exit")
+	config.DefineFlags(flags, core.Config{})
+	return flags
 }
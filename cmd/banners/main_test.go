@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSubcommandArgumentsAreNotFlags(t *testing.T) {
+	flags := newFlagSet("banners")
+	require.NoError(t, flags.Parse([]string{"--config", "test.yaml", "migrate", "force", "-1"}))
+
+	configPath, err := flags.GetString("config")
+	require.NoError(t, err)
+	require.Equal(t, "test.yaml", configPath)
+
+	args := flags.Args()
+	require.Equal(t, []string{"migrate", "force", "-1"}, args)
+	_, err = parseMigrateArgs(args[1:])
+	require.NoError(t, err)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"avito-tech-backend/internal/core"
+	"avito-tech-backend/internal/storage"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const migrateUsage = `usage: banners migrate <command>
+
+commands:
+  up             apply all pending migrations
+  down [N]       roll back the last N migrations (default 1)
+  goto V         migrate up or down to version V
+  status         print the applied and the latest version
+  force V        mark version V as applied and clear the dirty flag;
+                 -1 marks the database as not migrated`
+
+var errMigrateUsage = errors.New(migrateUsage)
+
+// runMigrate executes the migrate subcommand with args following "migrate".
+func runMigrate(cfg *core.Config, args []string, out io.Writer) error {
+	op, err := parseMigrateArgs(args)
+	if err != nil {
+		return err
+	}
+	migrator, err := storage.NewMigrator(cfg.Storage)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+	if err := op(migrator); err != nil {
+		return err
+	}
+	return printMigrationStatus(migrator, out)
+}
+
+// parseMigrateArgs checks the command line before any connection is made.
+func parseMigrateArgs(args []string) (func(*storage.Migrator) error, error) {
+	if len(args) == 0 {
+		return nil, errMigrateUsage
+	}
+	switch command, args := args[0], args[1:]; {
+	case command == "up" && len(args) == 0:
+		return (*storage.Migrator).Up, nil
+	case command == "down" && len(args) <= 1:
+		steps := 1
+		if len(args) == 1 {
+			var err error
+			steps, err = strconv.Atoi(args[0])
+			if err != nil || steps < 1 {
+				return nil, fmt.Errorf("invalid number of migrations %q", args[0])
+			}
+		}
+		return func(m *storage.Migrator) error { return m.Down(steps) }, nil
+	case command == "goto" && len(args) == 1:
+		version, err := strconv.ParseUint(args[0], 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q", args[0])
+		}
+		return func(m *storage.Migrator) error { return m.Goto(uint(version)) }, nil
+	case command == "force" && len(args) == 1:
+		version, err := strconv.Atoi(args[0])
+		if err != nil || version < -1 {
+			return nil, fmt.Errorf("invalid version %q", args[0])
+		}
+		return func(m *storage.Migrator) error { return m.Force(version) }, nil
+	case command == "status" && len(args) == 0:
+		return func(*storage.Migrator) error { return nil }, nil
+	default:
+		return nil, errMigrateUsage
+	}
+}
+
+func printMigrationStatus(migrator *storage.Migrator, out io.Writer) error {
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	state := "up to date"
+	switch {
+	case status.Dirty:
+		state = "dirty, fix the schema by hand and run force"
+	case status.Version < status.Latest:
+		state = fmt.Sprintf("%d pending", status.Latest-status.Version)
+	case status.Version > status.Latest:
+		state = "ahead of this binary"
+	}
+	_, err = fmt.Fprintf(out, "version %d, latest %d: %s\n", status.Version, status.Latest, state)
+	return err
+}
+
+// upMigrations applies pending migrations on boot.
+func upMigrations(cfg *core.Config) error {
+	migrator, err := storage.NewMigrator(cfg.Storage)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+	return migrator.Up()
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseMigrateArgs(t *testing.T) {
+	for _, args := range [][]string{
+		{"up"},
+		{"down"},
+		{"down", "2"},
+		{"goto", "3"},
+		{"force", "5"},
+		{"force", "-1"},
+		{"status"},
+	} {
+		_, err := parseMigrateArgs(args)
+		require.NoError(t, err, args)
+	}
+	for _, args := range [][]string{
+		{},
+		{"sideways"},
+		{"up", "1"},
+		{"down", "0"},
+		{"goto", "-1"},
+		{"force", "-2"},
+		{"force"},
+	} {
+		_, err := parseMigrateArgs(args)
+		require.Error(t, err, args)
+	}
+}
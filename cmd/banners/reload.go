@@ -0,0 +1,73 @@
+package main
+
+import (
+	"avito-tech-backend/internal/core"
+	http_server "avito-tech-backend/internal/http-server"
+	"avito-tech-backend/internal/pkg/logging"
+	"context"
+	"github.com/spf13/viper"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+)
+
+// reloadOnHangup re-reads the config on SIGHUP and applies the settings that
+// are safe to change at runtime. An invalid config is logged and ignored.
+func reloadOnHangup(ctx context.Context, loader *viper.Viper, current *core.Config, app *http_server.App) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	applied := *current
+	// The cache warmer only keeps refreshing if it did so from the start.
+	refreshing := current.Cache.RefreshAhead > 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+		}
+		cfg, err := core.ParseConfig(loader)
+		if err != nil {
+			slog.Error("Config reload failed, keeping the current config", "error", err)
+			continue
+		}
+		restartNeeded, err := applyConfig(app, &applied, cfg, refreshing)
+		if err != nil {
+			slog.Error("Config reload failed, keeping the current config", "error", err)
+			continue
+		}
+		if restartNeeded {
+			slog.Warn("Config changes other than log level, rate limits, cache TTL and refresh-ahead take effect after a restart")
+		}
+		slog.Info("Config reloaded", "log_level", cfg.Log.Level, "rate_limit_enabled", cfg.RateLimit.Enabled,
+			"cache_ttl", applied.Cache.TTL, "cache_refresh_ahead", applied.Cache.RefreshAhead)
+	}
+}
+
+// applyConfig applies the log level, rate limits, cache TTL and
+// refresh-ahead of cfg and records them in applied. Unless refreshing,
+// refresh-ahead stays zero. It reports whether cfg differs from applied in
+// settings that need a restart.
+func applyConfig(app *http_server.App, applied *core.Config, cfg *core.Config, refreshing bool) (bool, error) {
+	if err := logging.SetLevel(cfg.Log.Level); err != nil {
+		return false, err
+	}
+	app.SetRateLimits(cfg.RateLimit)
+	cache := cfg.Cache
+	if !refreshing {
+		cache.RefreshAhead = 0
+	}
+	app.Repository.Actions.SetCacheConfig(cache)
+
+	applied.Log.Level = cfg.Log.Level
+	// The limiter is sized once at startup.
+	maxBuckets := applied.RateLimit.MaxBuckets
+	applied.RateLimit = cfg.RateLimit
+	applied.RateLimit.MaxBuckets = maxBuckets
+	applied.Cache.TTL = cache.TTL
+	applied.Cache.RefreshAhead = cache.RefreshAhead
+	return !reflect.DeepEqual(*applied, *cfg), nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"avito-tech-backend/internal/core"
+	"avito-tech-backend/internal/core/actions"
+	http_server "avito-tech-backend/internal/http-server"
+	"avito-tech-backend/internal/pkg/cache"
+	"avito-tech-backend/internal/pkg/ratelimit"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestApplyConfigCacheSettings(t *testing.T) {
+	startup := core.Config{Cache: cache.Config{TTL: 5 * time.Minute, RefreshAhead: 30 * time.Second}}
+	startup.Log.Level = "info"
+	newApp := func() *http_server.App {
+		return &http_server.App{
+			Repository: &core.Repository{Config: &startup, Actions: actions.NewActions(nil, startup.Cache, nil)},
+			Limiter:    ratelimit.NewMemoryLimiter(10),
+		}
+	}
+	reloaded := startup
+	reloaded.Cache = cache.Config{TTL: 20 * time.Second, RefreshAhead: 10 * time.Second}
+
+	app := newApp()
+	applied := startup
+	restartNeeded, err := applyConfig(app, &applied, &reloaded, true)
+	require.NoError(t, err)
+	require.False(t, restartNeeded)
+	require.Equal(t, 20*time.Second, app.Repository.Actions.CacheTTL())
+	require.Equal(t, 10*time.Second, app.Repository.Actions.CacheRefreshAhead(),
+		"refresh-ahead must follow the TTL, or it could outlast it")
+
+	app = newApp()
+	applied = startup
+	restartNeeded, err = applyConfig(app, &applied, &reloaded, false)
+	require.NoError(t, err)
+	require.True(t, restartNeeded, "refreshing cannot be turned on without a restart")
+	require.Equal(t, 20*time.Second, app.Repository.Actions.CacheTTL())
+	require.Zero(t, app.Repository.Actions.CacheRefreshAhead())
+}
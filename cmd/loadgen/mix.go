@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultMix = "user_banner=85,user_banner_list=5,admin_list=4,admin_get=4,admin_update=2"
+
+// operation builds one kind of request for a seeded banner.
+type operation struct {
+	Name  string
+	Admin bool
+	Build func(base string, t target, rnd *rand.Rand, lastVersion bool) (*http.Request, error)
+}
+
+var operations = map[string]operation{
+	"user_banner": {
+		Name: "user_banner",
+		Build: func(base string, t target, _ *rand.Rand, lastVersion bool) (*http.Request, error) {
+			return http.NewRequest(http.MethodGet, base+"/user_banner?"+userQuery(t, lastVersion).Encode(), nil)
+		},
+	},
+	"user_banner_list": {
+		Name: "user_banner_list",
+		Build: func(base string, t target, _ *rand.Rand, lastVersion bool) (*http.Request, error) {
+			return http.NewRequest(http.MethodGet, base+"/user_banner/list?"+userQuery(t, lastVersion).Encode(), nil)
+		},
+	},
+	"admin_list": {
+		Name:  "admin_list",
+		Admin: true,
+		Build: func(base string, t target, _ *rand.Rand, _ bool) (*http.Request, error) {
+			return http.NewRequest(http.MethodGet, fmt.Sprintf("%s/banner?feature_id=%d&limit=10", base, t.FeatureId), nil)
+		},
+	},
+	"admin_get": {
+		Name:  "admin_get",
+		Admin: true,
+		Build: func(base string, t target, _ *rand.Rand, _ bool) (*http.Request, error) {
+			return http.NewRequest(http.MethodGet, fmt.Sprintf("%s/banner/%d", base, t.BannerId), nil)
+		},
+	},
+	"admin_update": {
+		Name:  "admin_update",
+		Admin: true,
+		Build: func(base string, t target, rnd *rand.Rand, _ bool) (*http.Request, error) {
+			body := fmt.Sprintf(`{"content":"{\"title\":\"loadgen update %d\"}"}`, rnd.Int63())
+			req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/banner/%d", base, t.BannerId), bytes.NewBufferString(body))
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Content-Type", "application/json")
+			return req, nil
+		},
+	},
+}
+
+func userQuery(t target, lastVersion bool) url.Values {
+	query := url.Values{}
+	query.Set("tag_id", strconv.FormatInt(t.TagId, 10))
+	query.Set("feature_id", strconv.FormatInt(t.FeatureId, 10))
+	if lastVersion {
+		query.Set("use_last_version", "true")
+	}
+	return query
+}
+
+type weighted struct {
+	op     operation
+	weight int
+}
+
+// mix picks operations at random in proportion to their weights.
+type mix struct {
+	ops   []weighted
+	total int
+}
+
+// parseMix reads weights written as "name=weight,name=weight".
+func parseMix(s string) (*mix, error) {
+	m := &mix{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("mix entry %q is not name=weight", part)
+		}
+		op, ok := operations[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown operation %q, expected one of %s", name, strings.Join(operationNames(), ", "))
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("weight of %q must be a non-negative integer", name)
+		}
+		if weight == 0 {
+			continue
+		}
+		m.ops = append(m.ops, weighted{op: op, weight: weight})
+		m.total += weight
+	}
+	if m.total == 0 {
+		return nil, fmt.Errorf("mix %q has no operations", s)
+	}
+	return m, nil
+}
+
+func (m *mix) pick(rnd *rand.Rand) operation {
+	n := rnd.Intn(m.total)
+	for _, w := range m.ops {
+		if n < w.weight {
+			return w.op
+		}
+		n -= w.weight
+	}
+	return m.ops[len(m.ops)-1].op
+}
+
+func operationNames() []string {
+	names := make([]string, 0, len(operations))
+	for name := range operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
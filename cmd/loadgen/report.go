@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+type opStats struct {
+	latencies []time.Duration
+	statuses  map[string]int
+	errors    int
+}
+
+type stats struct {
+	mu      sync.Mutex
+	ops     map[string]*opStats
+	dropped int
+}
+
+func newStats() *stats {
+	return &stats{ops: make(map[string]*opStats)}
+}
+
+// record accounts one request. Transport failures, server errors and
+// unexpected client errors count as errors; 404 is a valid answer for
+// banners hidden by targeting and 304 for revalidated ones.
+func (s *stats) record(op string, latency time.Duration, status int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.ops[op]
+	if !ok {
+		st = &opStats{statuses: make(map[string]int)}
+		s.ops[op] = st
+	}
+	if err != nil {
+		st.statuses["transport_error"]++
+		st.errors++
+		return
+	}
+	st.latencies = append(st.latencies, latency)
+	st.statuses[strconv.Itoa(status)]++
+	if status >= http.StatusBadRequest && status != http.StatusNotFound {
+		st.errors++
+	}
+}
+
+func (s *stats) drop() {
+	s.mu.Lock()
+	s.dropped++
+	s.mu.Unlock()
+}
+
+// Latency holds latency percentiles in milliseconds.
+type Latency struct {
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// Summary describes the requests of one operation or of the whole run.
+type Summary struct {
+	Requests   int            `json:"requests"`
+	Errors     int            `json:"errors"`
+	ErrorRate  float64        `json:"error_rate"`
+	Throughput float64        `json:"throughput_rps"`
+	LatencyMs  Latency        `json:"latency_ms"`
+	Statuses   map[string]int `json:"statuses"`
+}
+
+// Report is the outcome of a load test run.
+type Report struct {
+	Target       string             `json:"target"`
+	Mix          string             `json:"mix"`
+	RequestedRPS float64            `json:"requested_rps"`
+	Concurrency  int                `json:"concurrency"`
+	Elapsed      float64            `json:"elapsed_seconds"`
+	Dropped      int                `json:"dropped"`
+	Total        Summary            `json:"total"`
+	Operations   map[string]Summary `json:"operations"`
+}
+
+func (s *stats) report(opts options, elapsed time.Duration) *Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	report := &Report{
+		Target:       opts.Target,
+		Mix:          opts.Mix,
+		RequestedRPS: opts.RPS,
+		Concurrency:  opts.Concurrency,
+		Elapsed:      elapsed.Seconds(),
+		Dropped:      s.dropped,
+		Operations:   make(map[string]Summary, len(s.ops)),
+	}
+	all := &opStats{statuses: make(map[string]int)}
+	for name, st := range s.ops {
+		report.Operations[name] = summarize(st, elapsed)
+		all.latencies = append(all.latencies, st.latencies...)
+		all.errors += st.errors
+		for status, n := range st.statuses {
+			all.statuses[status] += n
+		}
+	}
+	report.Total = summarize(all, elapsed)
+	return report
+}
+
+func summarize(st *opStats, elapsed time.Duration) Summary {
+	// Every request lands in exactly one status, failed ones included.
+	requests := 0
+	for _, n := range st.statuses {
+		requests += n
+	}
+	summary := Summary{
+		Requests:  requests,
+		Errors:    st.errors,
+		LatencyMs: latency(st.latencies),
+		Statuses:  st.statuses,
+	}
+	if requests > 0 {
+		summary.ErrorRate = float64(st.errors) / float64(requests)
+	}
+	if elapsed > 0 {
+		summary.Throughput = float64(requests) / elapsed.Seconds()
+	}
+	return summary
+}
+
+func latency(latencies []time.Duration) Latency {
+	if len(latencies) == 0 {
+		return Latency{}
+	}
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var sum time.Duration
+	for _, l := range sorted {
+		sum += l
+	}
+	return Latency{
+		Mean: ms(sum / time.Duration(len(sorted))),
+		P50:  ms(percentile(sorted, 0.50)),
+		P90:  ms(percentile(sorted, 0.90)),
+		P95:  ms(percentile(sorted, 0.95)),
+		P99:  ms(percentile(sorted, 0.99)),
+		Max:  ms(sorted[len(sorted)-1]),
+	}
+}
+
+// percentile uses the nearest-rank method on sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteSummary prints the report as a table, one row per operation.
+func (r *Report) WriteSummary(w io.Writer) error {
+	fmt.Fprintf(w, "\n%s: %d requests in %.1fs (%.1f rps of %.0f requested), %d dropped, error rate %.2f%%\n\n",
+		r.Target, r.Total.Requests, r.Elapsed, r.Total.Throughput, r.RequestedRPS, r.Dropped, r.Total.ErrorRate*100)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "operation\trequests\terrors\trps\tmean\tp50\tp90\tp95\tp99\tmax\t")
+	names := make([]string, 0, len(r.Operations))
+	for name := range r.Operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeRow(tw, name, r.Operations[name])
+	}
+	writeRow(tw, "total", r.Total)
+	return tw.Flush()
+}
+
+func writeRow(w io.Writer, name string, s Summary) {
+	l := s.LatencyMs
+	fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%.1fms\t%.1fms\t%.1fms\t%.1fms\t%.1fms\t%.1fms\t\n",
+		name, s.Requests, s.Errors, s.Throughput, l.Mean, l.P50, l.P90, l.P95, l.P99, l.Max)
+}
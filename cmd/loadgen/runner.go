@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type job struct {
+	op  operation
+	req *http.Request
+}
+
+// runner sends requests at a fixed rate regardless of how fast the server
+// answers, so that a slow server shows up as latency rather than as a lower
+// request rate. Requests that find every worker busy are dropped and counted.
+type runner struct {
+	opts    options
+	mix     *mix
+	targets []target
+	rnd     *rand.Rand
+	client  *http.Client
+	stats   *stats
+}
+
+func newRunner(opts options, mix *mix, targets []target, rnd *rand.Rand) *runner {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = opts.Concurrency
+	transport.MaxIdleConnsPerHost = opts.Concurrency
+	return &runner{
+		opts:    opts,
+		mix:     mix,
+		targets: targets,
+		rnd:     rnd,
+		client:  &http.Client{Transport: transport, Timeout: opts.Timeout},
+		stats:   newStats(),
+	}
+}
+
+func (r *runner) Run(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, r.opts.Duration)
+	defer cancel()
+
+	jobs := make(chan job)
+	var wg sync.WaitGroup
+	for i := 0; i < r.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				r.do(j)
+			}
+		}()
+	}
+
+	interval := time.Duration(float64(time.Second) / r.opts.RPS)
+	start := time.Now()
+	next := start
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-timer.C:
+		}
+		// Catch up on every tick that is due so that timer granularity
+		// does not lower the rate.
+		for now := time.Now(); !next.After(now); next = next.Add(interval) {
+			j, err := r.next()
+			if err != nil {
+				r.stats.record(j.op.Name, 0, 0, err)
+				continue
+			}
+			select {
+			case jobs <- j:
+			default:
+				r.stats.drop()
+			}
+		}
+		timer.Reset(time.Until(next))
+	}
+	close(jobs)
+	wg.Wait()
+	return r.stats.report(r.opts, time.Since(start))
+}
+
+func (r *runner) next() (job, error) {
+	op := r.mix.pick(r.rnd)
+	t := r.targets[r.rnd.Intn(len(r.targets))]
+	lastVersion := r.rnd.Float64() < r.opts.LastVersion
+	req, err := op.Build(r.opts.Target, t, r.rnd, lastVersion)
+	if err != nil {
+		return job{op: op}, err
+	}
+	if op.Admin {
+		req.Header.Set("token", r.opts.AdminToken)
+	} else {
+		req.Header.Set("token", r.opts.UserToken)
+	}
+	return job{op: op, req: req}, nil
+}
+
+func (r *runner) do(j job) {
+	start := time.Now()
+	resp, err := r.client.Do(j.req)
+	if err != nil {
+		r.stats.record(j.op.Name, time.Since(start), 0, err)
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	r.stats.record(j.op.Name, time.Since(start), resp.StatusCode, nil)
+}
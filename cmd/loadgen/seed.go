@@ -0,0 +1,101 @@
+package main
+
+import (
+	"avito-tech-backend/internal/pkg/access"
+	"avito-tech-backend/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+var errNoBanners = errors.New("no banners to send requests for")
+
+// target is a banner together with one (tag, feature) pair it is served by.
+type target struct {
+	BannerId  int64
+	TagId     int64
+	FeatureId int64
+}
+
+// seed creates features, tags and banners spread randomly over them. Names
+// carry the run start time so that repeated runs do not collide.
+func seed(ctx context.Context, st *storage.Storage, rnd *rand.Rand, opts options) ([]target, error) {
+	run := time.Now().Format("20060102-150405")
+
+	featureIds := make([]int64, 0, opts.Features)
+	for i := 0; i < opts.Features; i++ {
+		feature, err := st.Features.InsertFeature(ctx, storage.FeatureCreateParams{
+			Name:  fmt.Sprintf("loadgen-%s-feature-%d", run, i),
+			Owner: "loadgen",
+		})
+		if err != nil {
+			return nil, err
+		}
+		featureIds = append(featureIds, feature.ID)
+	}
+	tagIds := make([]int64, 0, opts.Tags)
+	for i := 0; i < opts.Tags; i++ {
+		tag, err := st.Tags.InsertTag(ctx, storage.TagCreateParams{
+			Name:  fmt.Sprintf("loadgen-%s-tag-%d", run, i),
+			Owner: "loadgen",
+		})
+		if err != nil {
+			return nil, err
+		}
+		tagIds = append(tagIds, tag.ID)
+	}
+	if len(featureIds) == 0 || len(tagIds) == 0 {
+		return nil, errNoBanners
+	}
+
+	targets := make([]target, 0, opts.Banners)
+	for i := 0; i < opts.Banners; i++ {
+		banner, err := st.Banners.InsertBanner(ctx, storage.BannerCreateParams{
+			TagIds:    pickTags(rnd, tagIds),
+			FeatureId: featureIds[rnd.Intn(len(featureIds))],
+			Content:   fmt.Sprintf(`{"title":"loadgen %s #%d","url":"https://example.com/%d"}`, run, i, i),
+			IsActive:  true,
+			Priority:  rnd.Int63n(10),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, tagId := range banner.TagIds {
+			targets = append(targets, target{BannerId: banner.ID, TagId: tagId, FeatureId: banner.FeatureId})
+		}
+	}
+	return targets, nil
+}
+
+// loadTargets reuses banners already in the database.
+func loadTargets(ctx context.Context, st *storage.Storage, limit int) ([]target, error) {
+	banners, err := st.Banners.GetAllBannersByTagAndOrFeature(ctx, 0, 0, access.Scope{}, uint64(limit), 0)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]target, 0, len(banners))
+	for _, banner := range banners {
+		if !banner.IsActive {
+			continue
+		}
+		for _, tagId := range banner.TagIds {
+			targets = append(targets, target{BannerId: banner.ID, TagId: tagId, FeatureId: banner.FeatureId})
+		}
+	}
+	return targets, nil
+}
+
+// pickTags returns one to three distinct tags.
+func pickTags(rnd *rand.Rand, tagIds []int64) []int64 {
+	n := 1 + rnd.Intn(3)
+	if n > len(tagIds) {
+		n = len(tagIds)
+	}
+	picked := make([]int64, 0, n)
+	for _, i := range rnd.Perm(len(tagIds))[:n] {
+		picked = append(picked, tagIds[i])
+	}
+	return picked
+}
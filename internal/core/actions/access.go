@@ -0,0 +1,54 @@
+package actions
+
+import (
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/core/errs"
+	"avito-tech-backend/internal/pkg/access"
+	"context"
+)
+
+var ErrOutOfScope = errs.Forbidden("out_of_scope", "token does not grant access to these features or tags")
+
+// checkScope fails with ErrOutOfScope listing the feature and tag ids the
+// caller's scope does not cover.
+func checkScope(ctx context.Context, featureIds []int64, tagIds []int64) error {
+	features, tags := access.FromContext(ctx).Violations(featureIds, tagIds)
+	if len(features) == 0 && len(tags) == 0 {
+		return nil
+	}
+	err := ErrOutOfScope
+	if len(features) != 0 {
+		err = err.WithDetail("feature_ids", features)
+	}
+	if len(tags) != 0 {
+		err = err.WithDetail("tag_ids", tags)
+	}
+	return err
+}
+
+func checkBannerScope(ctx context.Context, banner *entities.Banner) error {
+	return checkScope(ctx, []int64{banner.FeatureId}, banner.TagIds)
+}
+
+// checkChangesScope checks the feature and tags a banner would have after
+// changes are applied.
+func checkChangesScope(ctx context.Context, banner *entities.Banner, changes entities.RawBanner) error {
+	featureId, tagIds := banner.FeatureId, banner.TagIds
+	if changes.FeatureId != nil {
+		featureId = *changes.FeatureId
+	}
+	if changes.TagIds != nil {
+		tagIds = *changes.TagIds
+	}
+	return checkScope(ctx, []int64{featureId}, tagIds)
+}
+
+// checkBannerIdScope checks the scope against the banner with id. Missing
+// banners pass: callers report them on their own terms.
+func (a *Actions) checkBannerIdScope(ctx context.Context, id int64) error {
+	banner, err := a.storage.Banners.FindBannerById(ctx, id)
+	if err != nil || banner == nil {
+		return err
+	}
+	return checkBannerScope(ctx, banner)
+}
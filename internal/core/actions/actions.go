@@ -2,62 +2,616 @@ package actions
 
 import (
 	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/core/errs"
+	"avito-tech-backend/internal/core/targeting"
+	"avito-tech-backend/internal/pkg/access"
+	"avito-tech-backend/internal/pkg/cache"
+	"avito-tech-backend/internal/pkg/impressions"
+	"avito-tech-backend/internal/pkg/pgdb"
+	"avito-tech-backend/internal/pkg/tenancy"
 	"avito-tech-backend/internal/storage"
 	"context"
+	"fmt"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/text/language"
+	"sort"
+	"sync/atomic"
+	"time"
 )
 
+var (
+	ErrBannerNotFound   = errs.NotFound("banner_not_found", "banner not found")
+	ErrBannerNotInTrash = errs.NotFound("banner_not_in_trash", "banner not found in trash")
+	ErrStaleBanner      = errs.PreconditionFailed("stale_banner", "banner was modified since the given version")
+	ErrFeatureNotFound  = errs.NotFound("feature_not_found", "feature not found")
+	ErrTagNotFound      = errs.NotFound("tag_not_found", "tag not found")
+	ErrUnknownFeature   = errs.Validation("unknown_feature", "banner references an unknown feature")
+	ErrUnknownTags      = errs.Validation("unknown_tags", "banner references unknown tags")
+	ErrInvalidTargeting = errs.Validation("invalid_targeting", "banner targeting rules are malformed")
+	ErrInvalidLocale    = errs.Validation("invalid_locale", "locale is not a valid BCP 47 tag")
+	ErrFeatureInUse     = errs.Conflict("feature_in_use", "feature is referenced by banners")
+	ErrTagInUse         = errs.Conflict("tag_in_use", "tag is referenced by banners")
+)
+
+// BannerKey identifies the banner a user sees.
+type BannerKey struct {
+	Tenant    string `json:"tenant"`
+	TagId     int64  `json:"tag_id"`
+	FeatureId int64  `json:"feature_id"`
+}
+
+// CacheStats describes the user banner cache.
+type CacheStats struct {
+	cache.Stats
+	Keys []BannerKey
+	// Truncated is set when there are more keys than were asked for.
+	Truncated bool
+	// Fetches counts database queries made to fill the cache, and Coalesced
+	// the lookups that waited for a query another one had started instead.
+	Fetches   uint64
+	Coalesced uint64
+}
+
 type Actions struct {
-	storage *storage.Storage
+	storage     *storage.Storage
+	bannerCache *cache.Cache[BannerKey, []entities.Banner]
+	// refreshAhead is swapped on config reload along with the cache TTL.
+	refreshAhead atomic.Int64
+	impressions  impressions.Store
+	// knownTenants remembers tenants found to exist, as every request
+	// checks its tenant.
+	knownTenants *cache.Cache[string, struct{}]
+	// requests is nil unless a top-N warm-up needs them ranked.
+	requests *requestCounts
+
+	loads     singleflight.Group
+	fetches   atomic.Uint64
+	coalesced atomic.Uint64
+}
+
+func NewActions(storage *storage.Storage, cacheCfg cache.Config, impressions impressions.Store) *Actions {
+	a := &Actions{
+		storage:     storage,
+		bannerCache: cache.New[BannerKey, []entities.Banner](cacheCfg.TTL),
+		impressions: impressions,
+
+		knownTenants: cache.New[string, struct{}](knownTenantTTL),
+	}
+	a.refreshAhead.Store(int64(cacheCfg.RefreshAhead))
+	if cacheCfg.Warmup.Enabled && cacheCfg.Warmup.TopN > 0 {
+		a.requests = newRequestCounts()
+	}
+	return a
+}
+
+// SetCacheConfig changes how long user banners are cached from now on and
+// how long before expiry read ones are refreshed. Warm-up settings are
+// ignored.
+func (a *Actions) SetCacheConfig(cfg cache.Config) {
+	a.bannerCache.SetTTL(cfg.TTL)
+	a.refreshAhead.Store(int64(cfg.RefreshAhead))
+}
+
+// CacheTTL returns how long user banners are cached.
+func (a *Actions) CacheTTL() time.Duration {
+	return a.bannerCache.TTL()
+}
+
+// CacheRefreshAhead returns how long before expiry read user banners are
+// refreshed; zero means they are not.
+func (a *Actions) CacheRefreshAhead() time.Duration {
+	return time.Duration(a.refreshAhead.Load())
+}
+
+// GetCacheStats returns user banner cache counters and up to limit cached
+// keys, sorted.
+func (a *Actions) GetCacheStats(limit int) CacheStats {
+	keys, truncated := a.bannerCache.Keys(limit)
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Tenant != keys[j].Tenant {
+			return keys[i].Tenant < keys[j].Tenant
+		}
+		if keys[i].TagId != keys[j].TagId {
+			return keys[i].TagId < keys[j].TagId
+		}
+		return keys[i].FeatureId < keys[j].FeatureId
+	})
+	return CacheStats{
+		Stats:     a.bannerCache.Stats(),
+		Keys:      keys,
+		Truncated: truncated,
+		Fetches:   a.fetches.Load(),
+		Coalesced: a.coalesced.Load(),
+	}
+}
+
+// WarmUpCache loads active banners of all tenants into the user banner
+// cache and returns how many keys were filled. A positive topN limits it to
+// the (tag, feature) pairs most requested lately, and only their banners are
+// loaded.
+func (a *Actions) WarmUpCache(ctx context.Context, topN int) (int, error) {
+	var banners []entities.Banner
+	var wanted map[BannerKey]bool
+	if topN > 0 {
+		stats, err := a.storage.Banners.GetBannerPairStats(ctx, requestsSince(time.Now()))
+		if err != nil {
+			return 0, err
+		}
+		pairs := topPairs(stats, topN)
+		wanted = make(map[BannerKey]bool, len(pairs))
+		for _, pair := range pairs {
+			wanted[BannerKey{Tenant: pair.Tenant, TagId: pair.TagId, FeatureId: pair.FeatureId}] = true
+		}
+		banners, err = a.storage.Banners.GetActiveBannersForPairs(ctx, pairs)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		var err error
+		banners, err = a.storage.Banners.GetActiveBanners(ctx)
+		if err != nil {
+			return 0, err
+		}
+	}
+	// Banners come highest priority first, so every list keeps the order
+	// userBanners would have loaded it in.
+	byKey := make(map[BannerKey][]entities.Banner)
+	for _, banner := range banners {
+		for _, tagId := range banner.TagIds {
+			key := BannerKey{Tenant: banner.Tenant, TagId: tagId, FeatureId: banner.FeatureId}
+			if wanted == nil || wanted[key] {
+				byKey[key] = append(byKey[key], banner)
+			}
+		}
+	}
+	for key, banners := range byKey {
+		a.bannerCache.Set(key, banners)
+	}
+	return len(byKey), nil
+}
+
+// RefreshCache reloads cached user banners that were read and expire within
+// CacheRefreshAhead, so hot keys do not miss. It returns how many keys were
+// reloaded.
+func (a *Actions) RefreshCache(ctx context.Context) (int, error) {
+	within := a.CacheRefreshAhead()
+	if within <= 0 {
+		return 0, nil
+	}
+	refreshed := 0
+	for _, key := range a.bannerCache.Expiring(within) {
+		if _, err := a.loadBanners(tenancy.WithTenant(ctx, key.Tenant), key); err != nil {
+			return refreshed, err
+		}
+		refreshed++
+	}
+	return refreshed, nil
+}
+
+// GetUserBanner returns the highest-priority banner for the tag and feature
+// whose targeting rules accept the client. Unless useLastVersion is set the
+// result may be up to the cache TTL old.
+//
+// When userId is given, banners the user has already seen as often as their
+// frequency cap allows today are skipped in favour of the next one. A banner
+// for which cached reports true is one the client already holds and merely
+// revalidates, so it does not count as an impression. cached may be nil.
+func (a *Actions) GetUserBanner(ctx context.Context, tagId int64, featureId int64, client targeting.Context, userId string, useLastVersion bool, cached func(*entities.Banner) bool) (*entities.Banner, error) {
+	banners, err := a.selectUserBanners(ctx, tagId, featureId, client, userId, 1, useLastVersion, cached)
+	if err != nil {
+		return nil, err
+	}
+	if len(banners) == 0 {
+		return nil, ErrBannerNotFound
+	}
+	return &banners[0], nil
+}
+
+// GetUserBanners returns up to limit banners for the tag and feature that
+// target the client, ordered by priority. Each returned banner counts as an
+// impression towards its frequency cap.
+func (a *Actions) GetUserBanners(ctx context.Context, tagId int64, featureId int64, client targeting.Context, userId string, limit int, useLastVersion bool) ([]entities.Banner, error) {
+	return a.selectUserBanners(ctx, tagId, featureId, client, userId, limit, useLastVersion, nil)
 }
 
-func NewActions(storage *storage.Storage) *Actions {
-	return &Actions{
-		storage: storage,
+func (a *Actions) selectUserBanners(ctx context.Context, tagId int64, featureId int64, client targeting.Context, userId string, limit int, useLastVersion bool, cached func(*entities.Banner) bool) ([]entities.Banner, error) {
+	scope, _ := tenancy.FromContext(ctx)
+	key := BannerKey{Tenant: scope.Tenant, TagId: tagId, FeatureId: featureId}
+	candidates, err := a.userBanners(ctx, key, useLastVersion)
+	if err != nil {
+		return nil, err
+	}
+	a.countRequest(key, candidates)
+	banners := make([]entities.Banner, 0, limit)
+	for _, banner := range candidates {
+		if len(banners) == limit {
+			break
+		}
+		if !banner.Targeting.Matches(client) {
+			continue
+		}
+		taken, err := a.takeImpression(ctx, banner, userId, cached != nil && cached(&banner))
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			banners = append(banners, banner)
+		}
+	}
+	return banners, nil
+}
+
+// takeImpression counts showing banner to the user and reports whether the
+// banner is still within its frequency cap. Anonymous users are not capped.
+// A revalidated banner is only checked against the cap, not counted.
+func (a *Actions) takeImpression(ctx context.Context, banner entities.Banner, userId string, revalidated bool) (bool, error) {
+	if banner.FrequencyCap == 0 || userId == "" {
+		return true, nil
+	}
+	if revalidated {
+		return a.impressions.Allows(ctx, impressions.NewKey(userId, banner.ID, time.Now()), banner.FrequencyCap)
+	}
+	return a.impressions.Take(ctx, impressions.NewKey(userId, banner.ID, time.Now()), banner.FrequencyCap)
+}
+
+// userBanners returns active banners for key, highest priority first. The
+// slice may be shared with the cache and must not be modified.
+func (a *Actions) userBanners(ctx context.Context, key BannerKey, useLastVersion bool) ([]entities.Banner, error) {
+	if useLastVersion {
+		// Joining a query started earlier could miss a change the caller
+		// has just made, so last version reads are never coalesced.
+		banners, err := a.storage.Banners.GetActiveBannersByTagAndFeature(pgdb.WithPrimary(ctx), key.TagId, key.FeatureId)
+		if err != nil {
+			return nil, err
+		}
+		a.bannerCache.Set(key, banners)
+		return banners, nil
+	}
+	if banners, ok := a.bannerCache.Get(key); ok {
+		return banners, nil
+	}
+	return a.loadBanners(ctx, key)
+}
+
+// loadBanners queries active banners for key and caches them. Concurrent
+// loads of the same key share one query.
+func (a *Actions) loadBanners(ctx context.Context, key BannerKey) ([]entities.Banner, error) {
+	fetched := false
+	result := a.loads.DoChan(fmt.Sprintf("%q/%d/%d", key.Tenant, key.TagId, key.FeatureId), func() (any, error) {
+		fetched = true
+		a.fetches.Add(1)
+		// The query serves every caller waiting for it, so it must not be
+		// cancelled along with the one that started it. The database query
+		// timeout still bounds it.
+		banners, err := a.storage.Banners.GetActiveBannersByTagAndFeature(context.WithoutCancel(ctx), key.TagId, key.FeatureId)
+		if err != nil {
+			return nil, err
+		}
+		a.bannerCache.Set(key, banners)
+		return banners, nil
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-result:
+		// fetched is safe to read: the query function has returned before
+		// its result was sent.
+		if !fetched {
+			a.coalesced.Add(1)
+		}
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.([]entities.Banner), nil
 	}
 }
 
-func (a *Actions) GetUserBanner(ctx context.Context, tagId int64, featureId int64, useLastVersion bool) (*entities.Banner, error) {
-	banner, err := a.storage.Banners.FindBannerByTagIdAndFeatureIdAndUseLastVersion(ctx, tagId, featureId, useLastVersion)
+func (a *Actions) GetBanner(ctx context.Context, id int64) (*entities.Banner, error) {
+	banner, err := a.storage.Banners.FindBannerById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	if banner == nil {
-		return nil, nil
+		return nil, ErrBannerNotFound
+	}
+	if err := checkBannerScope(ctx, banner); err != nil {
+		return nil, err
 	}
-	return a.storage.Banners.GetBannerByTagAndFeature(ctx, tagId, featureId, useLastVersion)
+	return banner, nil
 }
 
-func (a *Actions) GetBanners(ctx context.Context, tagId int64, featureId int64, limit uint64, offset uint64) ([]entities.Banner, error) {
-	return a.storage.Banners.GetAllBannersByTagAndOrFeature(ctx, tagId, featureId, limit, offset)
+// GetBanners lists banners the caller's scope allows.
+func (a *Actions) GetBanners(ctx context.Context, tagId int64, featureId int64, limit uint64, offset uint64, embedNames bool) ([]entities.Banner, error) {
+	banners, err := a.storage.Banners.GetAllBannersByTagAndOrFeature(ctx, tagId, featureId, access.FromContext(ctx), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if embedNames {
+		if err := a.embedNames(ctx, banners); err != nil {
+			return nil, err
+		}
+	}
+	return banners, nil
 }
 
 func (a *Actions) UpdateBanner(ctx context.Context, request entities.RawBanner) (*entities.Banner, error) {
-	banner, err := a.storage.Banners.FindBannerById(ctx, request.ID)
+	banner, updated, err := a.updateBanner(ctx, request)
 	if err != nil {
 		return nil, err
 	}
+	a.invalidate(banner, updated)
+	return updated, nil
+}
+
+// updateBanner applies request and returns the banner before and after it.
+// It leaves the cache alone: inside a transaction, invalidating before the
+// commit would let a concurrent miss cache the old banner again.
+func (a *Actions) updateBanner(ctx context.Context, request entities.RawBanner) (*entities.Banner, *entities.Banner, error) {
+	// Read-modify-write: a lagging replica would hand out a stale banner.
+	ctx = pgdb.WithPrimary(ctx)
+	banner, err := a.storage.Banners.FindBannerById(ctx, request.ID)
+	if err != nil {
+		return nil, nil, err
+	}
 	if banner == nil {
-		return nil, nil
+		return nil, nil, ErrBannerNotFound
+	}
+	if err := checkBannerScope(ctx, banner); err != nil {
+		return nil, nil, err
+	}
+	if err := checkChangesScope(ctx, banner, request); err != nil {
+		return nil, nil, err
 	}
-	return a.storage.Banners.UpdateBannerById(ctx, request.ID, request)
+	var tagIds []int64
+	if request.TagIds != nil {
+		tagIds = *request.TagIds
+	}
+	if err := a.validateBanner(ctx, request.FeatureId, tagIds, request.Targeting); err != nil {
+		return nil, nil, err
+	}
+	if err := canonicalRawLocales(&request); err != nil {
+		return nil, nil, err
+	}
+	updated, err := a.storage.Banners.UpdateBannerById(ctx, request.ID, request)
+	if err != nil {
+		return nil, nil, err
+	}
+	if updated == nil {
+		if request.ExpectedVersion != nil {
+			return nil, nil, a.staleOrMissing(ctx, request.ID)
+		}
+		return nil, nil, ErrBannerNotFound
+	}
+	return banner, updated, nil
 }
 
 func (a *Actions) CreateBanner(ctx context.Context, request entities.Banner) (*entities.Banner, error) {
+	if err := checkScope(ctx, []int64{request.FeatureId}, request.TagIds); err != nil {
+		return nil, err
+	}
+	if err := a.validateBanner(ctx, &request.FeatureId, request.TagIds, request.Targeting); err != nil {
+		return nil, err
+	}
+	localizedContent, defaultLocale, err := canonicalLocales(request.LocalizedContent, request.DefaultLocale)
+	if err != nil {
+		return nil, err
+	}
 	return a.storage.Banners.InsertBanner(ctx, storage.BannerCreateParams{
 		TagIds:    request.TagIds,
 		FeatureId: request.FeatureId,
 		Content:   request.Content,
 		IsActive:  request.IsActive,
+		Priority:  request.Priority,
+		Targeting: request.Targeting,
+
+		LocalizedContent: localizedContent,
+		DefaultLocale:    defaultLocale,
+
+		FrequencyCap: request.FrequencyCap,
 	})
 }
 
 func (a *Actions) DeleteBanner(ctx context.Context, id int64) (*entities.Banner, error) {
+	ctx = pgdb.WithPrimary(ctx)
 	banner, err := a.storage.Banners.FindBannerById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	if banner == nil {
-		return nil, nil
+		return nil, ErrBannerNotFound
+	}
+	if err := checkBannerScope(ctx, banner); err != nil {
+		return nil, err
+	}
+	banner, err = a.storage.Banners.DeleteBannerById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if banner == nil {
+		return nil, ErrBannerNotFound
+	}
+	a.invalidate(banner)
+	return banner, nil
+}
+
+func (a *Actions) GetDeletedBanners(ctx context.Context, limit uint64, offset uint64) ([]entities.Banner, error) {
+	return a.storage.Banners.GetDeletedBanners(ctx, access.FromContext(ctx), limit, offset)
+}
+
+func (a *Actions) RestoreBanner(ctx context.Context, id int64) (*entities.Banner, error) {
+	ctx = pgdb.WithPrimary(ctx)
+	banner, err := a.storage.Banners.FindDeletedBannerById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if banner == nil {
+		return nil, ErrBannerNotInTrash
+	}
+	if err := checkBannerScope(ctx, banner); err != nil {
+		return nil, err
+	}
+	banner, err = a.storage.Banners.RestoreBannerById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if banner == nil {
+		return nil, ErrBannerNotInTrash
+	}
+	a.invalidate(banner)
+	return banner, nil
+}
+
+// PurgeDeletedBanners hard-deletes banners that stayed in the trash longer than retention.
+func (a *Actions) PurgeDeletedBanners(ctx context.Context, retention time.Duration) (int, error) {
+	purged, err := a.storage.Banners.PurgeDeletedBanners(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+	return len(purged), nil
+}
+
+// PurgeImpressions drops stored impression counters of past days.
+func (a *Actions) PurgeImpressions(ctx context.Context) (int64, error) {
+	return a.storage.Impressions.PurgeImpressions(ctx, impressions.Day(time.Now()))
+}
+
+// canonicalLocales validates locale tags and rewrites them in canonical
+// form, so that "EN_us" and "en-US" name the same content.
+func canonicalLocales(content map[string]string, defaultLocale string) (map[string]string, string, error) {
+	var canonical map[string]string
+	if content != nil {
+		canonical = make(map[string]string, len(content))
+		for locale, value := range content {
+			tag, err := language.Parse(locale)
+			if err != nil {
+				return nil, "", ErrInvalidLocale.WithDetail("locale", locale)
+			}
+			canonical[tag.String()] = value
+		}
+	}
+	if defaultLocale != "" {
+		tag, err := language.Parse(defaultLocale)
+		if err != nil {
+			return nil, "", ErrInvalidLocale.WithDetail("locale", defaultLocale)
+		}
+		defaultLocale = tag.String()
+	}
+	return canonical, defaultLocale, nil
+}
+
+func canonicalRawLocales(request *entities.RawBanner) error {
+	var content map[string]string
+	if request.LocalizedContent != nil {
+		content = *request.LocalizedContent
+	}
+	var defaultLocale string
+	if request.DefaultLocale != nil {
+		defaultLocale = *request.DefaultLocale
+	}
+	content, defaultLocale, err := canonicalLocales(content, defaultLocale)
+	if err != nil {
+		return err
+	}
+	if request.LocalizedContent != nil {
+		request.LocalizedContent = &content
+	}
+	if request.DefaultLocale != nil {
+		request.DefaultLocale = &defaultLocale
+	}
+	return nil
+}
+
+// staleOrMissing tells apart why a conditional update matched no rows.
+func (a *Actions) staleOrMissing(ctx context.Context, id int64) error {
+	banner, err := a.storage.Banners.FindBannerById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if banner == nil {
+		return ErrBannerNotFound
+	}
+	return ErrStaleBanner.WithDetail("version", banner.Version)
+}
+
+// invalidate drops cached user banners of this instance for every
+// (tag, feature) pair the given banner versions were reachable by.
+func (a *Actions) invalidate(banners ...*entities.Banner) {
+	for _, banner := range banners {
+		for _, tagId := range banner.TagIds {
+			a.bannerCache.Delete(BannerKey{Tenant: banner.Tenant, TagId: tagId, FeatureId: banner.FeatureId})
+		}
+	}
+}
+
+// validateBanner checks that the targeting rules are well-formed and that
+// the feature and tags a banner points to are registered.
+func (a *Actions) validateBanner(ctx context.Context, featureId *int64, tagIds []int64, rules *targeting.Rules) error {
+	// A lagging replica could miss a feature or tag that was just created.
+	ctx = pgdb.WithPrimary(ctx)
+	if err := rules.Validate(); err != nil {
+		return ErrInvalidTargeting.WithDetail("reason", err.Error())
+	}
+	if featureId != nil {
+		feature, err := a.storage.Features.FindFeatureById(ctx, *featureId)
+		if err != nil {
+			return err
+		}
+		if feature == nil {
+			return ErrUnknownFeature.WithDetail("feature_id", *featureId)
+		}
+	}
+	if len(tagIds) == 0 {
+		return nil
+	}
+	tags, err := a.storage.Tags.FindTagsByIds(ctx, tagIds)
+	if err != nil {
+		return err
+	}
+	known := make(map[int64]struct{}, len(tags))
+	for _, tag := range tags {
+		known[tag.ID] = struct{}{}
+	}
+	var missing []int64
+	for _, id := range tagIds {
+		if _, ok := known[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) != 0 {
+		return ErrUnknownTags.WithDetail("tag_ids", missing)
+	}
+	return nil
+}
+
+func (a *Actions) embedNames(ctx context.Context, banners []entities.Banner) error {
+	featureIds := make([]int64, 0, len(banners))
+	tagIds := make([]int64, 0)
+	for _, banner := range banners {
+		featureIds = append(featureIds, banner.FeatureId)
+		tagIds = append(tagIds, banner.TagIds...)
+	}
+	features, err := a.storage.Features.FindFeaturesByIds(ctx, featureIds)
+	if err != nil {
+		return err
+	}
+	tags, err := a.storage.Tags.FindTagsByIds(ctx, tagIds)
+	if err != nil {
+		return err
+	}
+	featureNames := make(map[int64]string, len(features))
+	for _, feature := range features {
+		featureNames[feature.ID] = feature.Name
+	}
+	tagNames := make(map[int64]string, len(tags))
+	for _, tag := range tags {
+		tagNames[tag.ID] = tag.Name
+	}
+	for i := range banners {
+		banners[i].Feature = &entities.NamedRef{ID: banners[i].FeatureId, Name: featureNames[banners[i].FeatureId]}
+		banners[i].Tags = make([]entities.NamedRef, 0, len(banners[i].TagIds))
+		for _, id := range banners[i].TagIds {
+			banners[i].Tags = append(banners[i].Tags, entities.NamedRef{ID: id, Name: tagNames[id]})
+		}
 	}
-	return a.storage.Banners.DeleteBannerById(ctx, id)
+	return nil
 }
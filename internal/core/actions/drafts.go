@@ -0,0 +1,209 @@
+package actions
+
+import (
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/core/errs"
+	"avito-tech-backend/internal/pkg/pgdb"
+	"avito-tech-backend/internal/storage"
+	"context"
+)
+
+const (
+	draftActionCreate  = "create"
+	draftActionSubmit  = "submit"
+	draftActionApprove = "approve"
+	draftActionReject  = "reject"
+)
+
+var (
+	ErrDraftNotFound     = errs.NotFound("draft_not_found", "draft not found")
+	ErrDraftState        = errs.Conflict("invalid_draft_state", "draft is not in a state that allows this action")
+	ErrSelfApproval      = errs.Forbidden("self_approval", "draft author cannot approve their own draft")
+	ErrNotDraftAuthor    = errs.Forbidden("not_draft_author", "only the draft author can submit it")
+	ErrEmptyDraftChanges = errs.Validation("empty_update", "you must pass at least one parameter")
+)
+
+// CreateDraft stores changes to a banner without making them live.
+func (a *Actions) CreateDraft(ctx context.Context, changes entities.RawBanner, author string, comment string) (*entities.BannerDraft, error) {
+	if changes.IsEmpty() {
+		return nil, ErrEmptyDraftChanges
+	}
+	// The draft is based on the banner version read here, so it must be current.
+	ctx = pgdb.WithPrimary(ctx)
+	banner, err := a.storage.Banners.FindBannerById(ctx, changes.ID)
+	if err != nil {
+		return nil, err
+	}
+	if banner == nil {
+		return nil, ErrBannerNotFound
+	}
+	if err := checkBannerScope(ctx, banner); err != nil {
+		return nil, err
+	}
+	if err := checkChangesScope(ctx, banner, changes); err != nil {
+		return nil, err
+	}
+	var tagIds []int64
+	if changes.TagIds != nil {
+		tagIds = *changes.TagIds
+	}
+	if err := a.validateBanner(ctx, changes.FeatureId, tagIds, changes.Targeting); err != nil {
+		return nil, err
+	}
+	if err := canonicalRawLocales(&changes); err != nil {
+		return nil, err
+	}
+
+	var draft *entities.BannerDraft
+	err = a.storage.Database.WithTransaction(ctx, func(ctx context.Context) error {
+		draft, err = a.storage.Drafts.InsertDraft(ctx, storage.DraftCreateParams{
+			BannerId:    banner.ID,
+			Changes:     changes,
+			BaseVersion: banner.Version,
+			Author:      author,
+		})
+		if err != nil {
+			return err
+		}
+		return a.addComment(ctx, draft, author, draftActionCreate, comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// GetDrafts returns all drafts of a banner, newest first, with their comments.
+func (a *Actions) GetDrafts(ctx context.Context, bannerId int64) ([]entities.BannerDraft, error) {
+	if err := a.checkBannerIdScope(ctx, bannerId); err != nil {
+		return nil, err
+	}
+	drafts, err := a.storage.Drafts.GetDraftsByBannerId(ctx, bannerId)
+	if err != nil {
+		return nil, err
+	}
+	if len(drafts) == 0 {
+		return drafts, nil
+	}
+	ids := make([]int64, 0, len(drafts))
+	byId := make(map[int64]*entities.BannerDraft, len(drafts))
+	for i := range drafts {
+		drafts[i].Comments = make([]entities.DraftComment, 0)
+		ids = append(ids, drafts[i].ID)
+		byId[drafts[i].ID] = &drafts[i]
+	}
+	comments, err := a.storage.Drafts.GetCommentsByDraftIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, comment := range comments {
+		byId[comment.DraftId].Comments = append(byId[comment.DraftId].Comments, comment)
+	}
+	return drafts, nil
+}
+
+// SubmitDraft sends the draft for review.
+func (a *Actions) SubmitDraft(ctx context.Context, draftId int64, actor string, comment string) (*entities.BannerDraft, error) {
+	draft, err := a.findDraft(ctx, draftId)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Author != actor {
+		return nil, ErrNotDraftAuthor
+	}
+	return a.transitDraft(ctx, draft, entities.DraftStatusDraft, entities.DraftStatusPending, actor, draftActionSubmit, comment, nil)
+}
+
+// ApproveDraft applies a pending draft to its banner. The banner must not
+// have changed since the draft was created, and the approver must not be
+// the draft author.
+func (a *Actions) ApproveDraft(ctx context.Context, draftId int64, reviewer string, comment string) (*entities.BannerDraft, error) {
+	draft, err := a.findDraft(ctx, draftId)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Author == reviewer {
+		return nil, ErrSelfApproval
+	}
+	var banner, updated *entities.Banner
+	approved, err := a.transitDraft(ctx, draft, entities.DraftStatusPending, entities.DraftStatusApproved, reviewer, draftActionApprove, comment, func(ctx context.Context) error {
+		changes := draft.Changes
+		changes.ExpectedVersion = &draft.BaseVersion
+		var err error
+		banner, updated, err = a.updateBanner(ctx, changes)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Only now is the new banner visible to the queries that refill the cache.
+	a.invalidate(banner, updated)
+	return approved, nil
+}
+
+// RejectDraft closes a pending draft without applying it.
+func (a *Actions) RejectDraft(ctx context.Context, draftId int64, reviewer string, comment string) (*entities.BannerDraft, error) {
+	draft, err := a.findDraft(ctx, draftId)
+	if err != nil {
+		return nil, err
+	}
+	return a.transitDraft(ctx, draft, entities.DraftStatusPending, entities.DraftStatusRejected, reviewer, draftActionReject, comment, nil)
+}
+
+func (a *Actions) findDraft(ctx context.Context, draftId int64) (*entities.BannerDraft, error) {
+	draft, err := a.storage.Drafts.FindDraftById(ctx, draftId)
+	if err != nil {
+		return nil, err
+	}
+	if draft == nil {
+		return nil, ErrDraftNotFound
+	}
+	if err := a.checkBannerIdScope(ctx, draft.BannerId); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// transitDraft atomically moves the draft between statuses, runs apply in
+// the same transaction and records the actor's comment.
+func (a *Actions) transitDraft(ctx context.Context, draft *entities.BannerDraft, from entities.DraftStatus, to entities.DraftStatus, actor string, action string, comment string, apply func(ctx context.Context) error) (*entities.BannerDraft, error) {
+	var updated *entities.BannerDraft
+	err := a.storage.Database.WithTransaction(ctx, func(ctx context.Context) error {
+		var reviewer *string
+		if to == entities.DraftStatusApproved || to == entities.DraftStatusRejected {
+			reviewer = &actor
+		}
+		var err error
+		updated, err = a.storage.Drafts.UpdateDraftStatus(ctx, draft.ID, from, to, reviewer)
+		if err != nil {
+			return err
+		}
+		if updated == nil {
+			return ErrDraftState.WithDetail("status", draft.Status)
+		}
+		if apply != nil {
+			if err := apply(ctx); err != nil {
+				return err
+			}
+		}
+		return a.addComment(ctx, updated, actor, action, comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (a *Actions) addComment(ctx context.Context, draft *entities.BannerDraft, author string, action string, body string) error {
+	comment, err := a.storage.Drafts.InsertComment(ctx, entities.DraftComment{
+		DraftId: draft.ID,
+		Author:  author,
+		Action:  action,
+		Body:    body,
+	})
+	if err != nil {
+		return err
+	}
+	draft.Comments = append(draft.Comments, *comment)
+	return nil
+}
@@ -0,0 +1,74 @@
+package actions
+
+import (
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/pkg/access"
+	"avito-tech-backend/internal/storage"
+	"context"
+)
+
+func (a *Actions) GetFeatures(ctx context.Context, includeArchived bool, limit uint64, offset uint64) ([]entities.Feature, error) {
+	return a.storage.Features.GetAllFeatures(ctx, includeArchived, access.FromContext(ctx), limit, offset)
+}
+
+func (a *Actions) GetFeature(ctx context.Context, id int64) (*entities.Feature, error) {
+	if err := checkScope(ctx, []int64{id}, nil); err != nil {
+		return nil, err
+	}
+	feature, err := a.storage.Features.FindFeatureById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if feature == nil {
+		return nil, ErrFeatureNotFound
+	}
+	return feature, nil
+}
+
+// CreateFeature is refused to tokens scoped to particular features, as the id
+// of the new feature cannot be known to lie in their scope.
+func (a *Actions) CreateFeature(ctx context.Context, request entities.Feature) (*entities.Feature, error) {
+	if len(access.FromContext(ctx).FeatureIds) != 0 {
+		return nil, ErrOutOfScope
+	}
+	return a.storage.Features.InsertFeature(ctx, storage.FeatureCreateParams{
+		Name:        request.Name,
+		Description: request.Description,
+		Owner:       request.Owner,
+	})
+}
+
+func (a *Actions) UpdateFeature(ctx context.Context, request entities.RawFeature) (*entities.Feature, error) {
+	if err := checkScope(ctx, []int64{request.ID}, nil); err != nil {
+		return nil, err
+	}
+	feature, err := a.storage.Features.UpdateFeatureById(ctx, request.ID, request)
+	if err != nil {
+		return nil, err
+	}
+	if feature == nil {
+		return nil, ErrFeatureNotFound
+	}
+	return feature, nil
+}
+
+func (a *Actions) DeleteFeature(ctx context.Context, id int64) (*entities.Feature, error) {
+	if err := checkScope(ctx, []int64{id}, nil); err != nil {
+		return nil, err
+	}
+	feature, err := a.storage.Features.FindFeatureById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if feature == nil {
+		return nil, ErrFeatureNotFound
+	}
+	inUse, err := a.storage.Banners.ExistsBannerByFeatureId(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if inUse {
+		return nil, ErrFeatureInUse.WithDetail("feature_id", id)
+	}
+	return a.storage.Features.DeleteFeatureById(ctx, id)
+}
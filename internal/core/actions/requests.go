@@ -0,0 +1,112 @@
+package actions
+
+import (
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/pkg/impressions"
+	"avito-tech-backend/internal/storage"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// requestWindow is how far back user banner requests count when ranking
+// pairs for a top-N warm-up.
+const requestWindow = 7 * 24 * time.Hour
+
+// requestCounts tallies user banner requests per key until they are
+// flushed to storage.
+type requestCounts struct {
+	mu     sync.Mutex
+	counts map[BannerKey]int64
+}
+
+func newRequestCounts() *requestCounts {
+	return &requestCounts{counts: make(map[BannerKey]int64)}
+}
+
+func (c *requestCounts) add(key BannerKey, n int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[key] += n
+}
+
+// take returns the counts so far and starts over.
+func (c *requestCounts) take() map[BannerKey]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := c.counts
+	c.counts = make(map[BannerKey]int64, len(counts))
+	return counts
+}
+
+// countRequest counts a user banner request for key, cache hit or miss.
+// Keys without banners are never warmed up, and skipping them keeps
+// requests for made-up pairs from growing the tally.
+func (a *Actions) countRequest(key BannerKey, banners []entities.Banner) {
+	if a.requests != nil && len(banners) > 0 {
+		a.requests.add(key, 1)
+	}
+}
+
+// FlushRequestCounts stores the user banner requests counted since the last
+// flush and returns for how many keys. Counts that could not be stored are
+// kept for the next flush.
+func (a *Actions) FlushRequestCounts(ctx context.Context) (int, error) {
+	if a.requests == nil {
+		return 0, nil
+	}
+	counts := a.requests.take()
+	pairs := make(map[storage.BannerPair]int64, len(counts))
+	for key, count := range counts {
+		pairs[storage.BannerPair{Tenant: key.Tenant, TagId: key.TagId, FeatureId: key.FeatureId}] = count
+	}
+	if err := a.storage.Requests.AddRequests(ctx, impressions.Day(time.Now()), pairs); err != nil {
+		for key, count := range counts {
+			a.requests.add(key, count)
+		}
+		return 0, err
+	}
+	return len(pairs), nil
+}
+
+// PurgeRequestCounts drops stored request counts too old to rank pairs.
+func (a *Actions) PurgeRequestCounts(ctx context.Context) (int64, error) {
+	return a.storage.Requests.PurgeRequests(ctx, requestsSince(time.Now()))
+}
+
+// requestsSince returns the first day whose requests rank pairs at now.
+func requestsSince(now time.Time) time.Time {
+	return impressions.Day(now.Add(-requestWindow))
+}
+
+// topPairs returns up to n pairs, most requested first. Pairs requested
+// equally often, such as ones never requested, follow their highest banner
+// priority.
+func topPairs(stats []storage.BannerPairStats, n int) []storage.BannerPair {
+	sorted := append([]storage.BannerPairStats(nil), stats...)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Requests != b.Requests {
+			return a.Requests > b.Requests
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if a.Tenant != b.Tenant {
+			return a.Tenant < b.Tenant
+		}
+		if a.TagId != b.TagId {
+			return a.TagId < b.TagId
+		}
+		return a.FeatureId < b.FeatureId
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	pairs := make([]storage.BannerPair, 0, len(sorted))
+	for _, pair := range sorted {
+		pairs = append(pairs, pair.BannerPair)
+	}
+	return pairs
+}
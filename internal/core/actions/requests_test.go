@@ -0,0 +1,37 @@
+package actions
+
+import (
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/storage"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTopPairsRankByRequests(t *testing.T) {
+	busy := storage.BannerPair{Tenant: "default", TagId: 1, FeatureId: 1}
+	idle := storage.BannerPair{Tenant: "default", TagId: 2, FeatureId: 1}
+	quiet := storage.BannerPair{Tenant: "default", TagId: 3, FeatureId: 1}
+	stats := []storage.BannerPairStats{
+		{BannerPair: idle, Requests: 0, Priority: 100},
+		{BannerPair: quiet, Requests: 0, Priority: 10},
+		// Its banners have no frequency cap, so no impressions are ever
+		// recorded for it.
+		{BannerPair: busy, Requests: 5000, Priority: 1},
+	}
+
+	require.Equal(t, []storage.BannerPair{busy}, topPairs(stats, 1))
+	require.Equal(t, []storage.BannerPair{busy, idle, quiet}, topPairs(stats, 5), "unrequested pairs follow by priority")
+}
+
+func TestCountRequests(t *testing.T) {
+	a := &Actions{requests: newRequestCounts()}
+	key := BannerKey{Tenant: "default", TagId: 1, FeatureId: 1}
+	banners := []entities.Banner{{ID: 1}}
+	a.countRequest(key, banners)
+	a.countRequest(key, banners)
+	a.countRequest(BannerKey{Tenant: "default", TagId: 1_000_000, FeatureId: 1}, nil)
+
+	require.Equal(t, map[BannerKey]int64{key: 2}, a.requests.take(), "requests for pairs without banners are not counted")
+	require.Empty(t, a.requests.take(), "taking the counts starts over")
+	require.NotPanics(t, func() { (&Actions{}).countRequest(key, banners) }, "counting is off without a top-N warm-up")
+}
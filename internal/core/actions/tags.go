@@ -0,0 +1,74 @@
+package actions
+
+import (
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/pkg/access"
+	"avito-tech-backend/internal/storage"
+	"context"
+)
+
+func (a *Actions) GetTags(ctx context.Context, includeArchived bool, limit uint64, offset uint64) ([]entities.Tag, error) {
+	return a.storage.Tags.GetAllTags(ctx, includeArchived, access.FromContext(ctx), limit, offset)
+}
+
+func (a *Actions) GetTag(ctx context.Context, id int64) (*entities.Tag, error) {
+	if err := checkScope(ctx, nil, []int64{id}); err != nil {
+		return nil, err
+	}
+	tag, err := a.storage.Tags.FindTagById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, ErrTagNotFound
+	}
+	return tag, nil
+}
+
+// CreateTag is refused to tokens scoped to particular tags, as the id
+// of the new tag cannot be known to lie in their scope.
+func (a *Actions) CreateTag(ctx context.Context, request entities.Tag) (*entities.Tag, error) {
+	if len(access.FromContext(ctx).TagRanges) != 0 {
+		return nil, ErrOutOfScope
+	}
+	return a.storage.Tags.InsertTag(ctx, storage.TagCreateParams{
+		Name:        request.Name,
+		Description: request.Description,
+		Owner:       request.Owner,
+	})
+}
+
+func (a *Actions) UpdateTag(ctx context.Context, request entities.RawTag) (*entities.Tag, error) {
+	if err := checkScope(ctx, nil, []int64{request.ID}); err != nil {
+		return nil, err
+	}
+	tag, err := a.storage.Tags.UpdateTagById(ctx, request.ID, request)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, ErrTagNotFound
+	}
+	return tag, nil
+}
+
+func (a *Actions) DeleteTag(ctx context.Context, id int64) (*entities.Tag, error) {
+	if err := checkScope(ctx, nil, []int64{id}); err != nil {
+		return nil, err
+	}
+	tag, err := a.storage.Tags.FindTagById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, ErrTagNotFound
+	}
+	inUse, err := a.storage.Banners.ExistsBannerByTagId(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if inUse {
+		return nil, ErrTagInUse.WithDetail("tag_id", id)
+	}
+	return a.storage.Tags.DeleteTagById(ctx, id)
+}
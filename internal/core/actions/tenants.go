@@ -0,0 +1,102 @@
+package actions
+
+import (
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/core/errs"
+	"avito-tech-backend/internal/pkg/pgdb"
+	"avito-tech-backend/internal/storage"
+	"context"
+	"regexp"
+	"time"
+)
+
+var (
+	ErrTenantNotFound    = errs.NotFound("tenant_not_found", "tenant not found")
+	ErrTenantExists      = errs.Conflict("tenant_exists", "tenant already exists")
+	ErrTenantInUse       = errs.Conflict("tenant_in_use", "tenant still owns banners, features or tags")
+	ErrInvalidTenantName = errs.Validation("invalid_tenant_name", "tenant name must be 1-63 lowercase letters, digits, '-' or '_'")
+)
+
+var tenantNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// knownTenantTTL bounds how long other instances keep accepting a tenant
+// after it is deleted.
+const knownTenantTTL = time.Minute
+
+// TenantExists reports whether the tenant is registered. Positive answers
+// are cached.
+func (a *Actions) TenantExists(ctx context.Context, name string) (bool, error) {
+	if _, ok := a.knownTenants.Get(name); ok {
+		return true, nil
+	}
+	// A replica may not have seen a tenant that was just created.
+	tenant, err := a.storage.Tenants.FindTenantByName(pgdb.WithPrimary(ctx), name)
+	if err != nil || tenant == nil {
+		return false, err
+	}
+	a.knownTenants.Set(name, struct{}{})
+	return true, nil
+}
+
+func (a *Actions) GetTenants(ctx context.Context, limit uint64, offset uint64) ([]entities.Tenant, error) {
+	return a.storage.Tenants.GetAllTenants(ctx, limit, offset)
+}
+
+func (a *Actions) GetTenant(ctx context.Context, name string) (*entities.Tenant, error) {
+	tenant, err := a.storage.Tenants.FindTenantByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return nil, ErrTenantNotFound
+	}
+	return tenant, nil
+}
+
+func (a *Actions) CreateTenant(ctx context.Context, request entities.Tenant) (*entities.Tenant, error) {
+	if !tenantNamePattern.MatchString(request.Name) {
+		return nil, ErrInvalidTenantName.WithDetail("name", request.Name)
+	}
+	tenant, err := a.storage.Tenants.InsertTenant(ctx, storage.TenantCreateParams{
+		Name:        request.Name,
+		Description: request.Description,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return nil, ErrTenantExists.WithDetail("name", request.Name)
+	}
+	return tenant, nil
+}
+
+func (a *Actions) UpdateTenant(ctx context.Context, request entities.RawTenant) (*entities.Tenant, error) {
+	tenant, err := a.storage.Tenants.UpdateTenantByName(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return nil, ErrTenantNotFound
+	}
+	return tenant, nil
+}
+
+// DeleteTenant removes a tenant that owns nothing anymore.
+func (a *Actions) DeleteTenant(ctx context.Context, name string) (*entities.Tenant, error) {
+	inUse, err := a.storage.Tenants.IsTenantInUse(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if inUse {
+		return nil, ErrTenantInUse.WithDetail("name", name)
+	}
+	tenant, err := a.storage.Tenants.DeleteTenantByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return nil, ErrTenantNotFound
+	}
+	a.knownTenants.Delete(name)
+	return tenant, nil
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/pkg/access"
+	"avito-tech-backend/internal/pkg/tenancy"
+	"crypto/subtle"
+)
+
+// AuthConfig lists API tokens and the principal each of them authenticates.
+type AuthConfig struct {
+	Tokens []TokenConfig `yaml:"tokens"`
+}
+
+type TokenConfig struct {
+	Token   string        `yaml:"token"`
+	Subject string        `yaml:"subject"`
+	Role    entities.Role `yaml:"role"`
+	// Tenant is the namespace the token acts in; empty means the default one.
+	Tenant string `yaml:"tenant"`
+	// Scope restricts an admin token to some features and tag ranges.
+	Scope access.Scope `yaml:"scope"`
+	// CertSubject lets callers presenting a verified client certificate with
+	// this common name authenticate without a token, over mutual TLS.
+	CertSubject string `yaml:"certSubject"`
+}
+
+// Lookup returns the principal for token.
+func (c AuthConfig) Lookup(token string) (entities.Principal, bool) {
+	for _, t := range c.Tokens {
+		if t.Token != "" && subtle.ConstantTimeCompare([]byte(t.Token), []byte(token)) == 1 {
+			return t.principal(), true
+		}
+	}
+	return entities.Principal{}, false
+}
+
+// LookupCert returns the principal for a verified client certificate.
+func (c AuthConfig) LookupCert(commonName string) (entities.Principal, bool) {
+	for _, t := range c.Tokens {
+		if t.CertSubject != "" && t.CertSubject == commonName {
+			return t.principal(), true
+		}
+	}
+	return entities.Principal{}, false
+}
+
+// NamesTenant reports whether any token acts in the tenant.
+func (c AuthConfig) NamesTenant(tenant string) bool {
+	for _, t := range c.Tokens {
+		if t.tenant() == tenant {
+			return true
+		}
+	}
+	return false
+}
+
+func (t TokenConfig) principal() entities.Principal {
+	return entities.Principal{Subject: t.Subject, Role: t.Role, Tenant: t.tenant(), Scope: t.Scope}
+}
+
+func (t TokenConfig) tenant() string {
+	if t.Tenant == "" {
+		return tenancy.Default
+	}
+	return t.Tenant
+}
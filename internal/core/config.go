@@ -1,23 +1,180 @@
 package core
 
 import (
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/core/jobs"
+	"avito-tech-backend/internal/pkg/cache"
+	"avito-tech-backend/internal/pkg/impressions"
+	"avito-tech-backend/internal/pkg/logging"
+	"avito-tech-backend/internal/pkg/ratelimit"
+	"avito-tech-backend/internal/pkg/tenancy"
 	"avito-tech-backend/internal/pkg/web"
 	"avito-tech-backend/internal/storage"
+	"errors"
+	"fmt"
 	"github.com/spf13/viper"
+	"net/url"
+	"time"
 )
 
+const redacted = "REDACTED"
+
 type Config struct {
-	Storage storage.Config   `yaml:"storage"`
-	Server  web.ServerConfig `yaml:"server"`
+	Storage   storage.Config    `yaml:"storage"`
+	Server    web.ServerConfig  `yaml:"server"`
+	RateLimit ratelimit.Config  `yaml:"rateLimit"`
+	Log       logging.Config    `yaml:"log"`
+	Cache     cache.Config      `yaml:"cache"`
+	Trash     jobs.PurgerConfig `yaml:"trash"`
+	Auth      AuthConfig        `yaml:"auth"`
+	Drafts    DraftsConfig      `yaml:"drafts"`
+
+	Impressions impressions.Config `yaml:"impressions"`
+}
+
+// DraftsConfig controls the banner review workflow.
+type DraftsConfig struct {
+	// RequireReview rejects direct banner updates, so that every change
+	// goes live through an approved draft.
+	RequireReview bool `yaml:"requireReview"`
 }
 
+// ParseConfig reads the config with the precedence set up on loader and
+// validates it. Keys the config does not know are rejected, so that a typo
+// does not silently leave a setting at its default.
 func ParseConfig(loader *viper.Viper) (*Config, error) {
 	cfg := &Config{}
+	loader.SetDefault("server.listen", ":8080")
+	loader.SetDefault("server.drainInterval", 5*time.Second)
+	loader.SetDefault("server.readinessTimeout", 2*time.Second)
+	loader.SetDefault("server.tls.reloadInterval", 10*time.Second)
+	loader.SetDefault("storage.autoMigrate", true)
+	loader.SetDefault("storage.replicaCheckInterval", 5*time.Second)
+	loader.SetDefault("storage.replicaCheckTimeout", time.Second)
+	loader.SetDefault("storage.pool.queryTimeout", 5*time.Second)
+	loader.SetDefault("storage.pool.applicationName", "banners")
+	// Users may see banners up to five minutes old unless they ask for the last revision.
+	loader.SetDefault("cache.ttl", 5*time.Minute)
+	loader.SetDefault("trash.retention", 30*24*time.Hour)
+	loader.SetDefault("trash.interval", time.Hour)
+	loader.SetDefault("rateLimit.maxBuckets", 100_000)
+	loader.SetDefault("impressions.shards", 16)
+	loader.SetDefault("impressions.maxEntries", 100_000)
+	loader.SetDefault("auth.tokens", []map[string]any{
+		{"token": "user_token", "subject": "user", "role": entities.RoleUser, "tenant": tenancy.Default},
+		{"token": "admin_token", "subject": "admin", "role": entities.RoleAdmin, "tenant": tenancy.Default},
+	})
 	if err := loader.ReadInConfig(); err != nil {
 		return nil, err
 	}
-	if err := loader.Unmarshal(cfg); err != nil {
+	if err := loader.UnmarshalExact(cfg); err != nil {
 		return nil, err
 	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
+	}
 	return cfg, nil
 }
+
+// Validate reports every invalid setting, one per line, by its config key.
+func (c *Config) Validate() error {
+	var problems []error
+	check := func(ok bool, key string, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	check(c.Storage.URL != "", "storage.url", "is required")
+	for i, replica := range c.Storage.Replicas {
+		check(replica != "", fmt.Sprintf("storage.replicas[%d]", i), "must not be empty")
+	}
+	check(c.Storage.ReplicaCheckTimeout > 0, "storage.replicaCheckTimeout", "must be positive")
+	pool := c.Storage.Pool
+	check(pool.MaxConns >= 0, "storage.pool.maxConns", "must not be negative")
+	check(pool.MinConns >= 0, "storage.pool.minConns", "must not be negative")
+	check(pool.MaxConns == 0 || pool.MinConns <= pool.MaxConns, "storage.pool.minConns", "must not exceed maxConns (%d)", pool.MaxConns)
+	check(pool.StatementTimeout >= 0, "storage.pool.statementTimeout", "must not be negative")
+	check(pool.QueryTimeout >= 0, "storage.pool.queryTimeout", "must not be negative")
+
+	check(c.Server.Listen != "", "server.listen", "is required")
+	check(c.Server.DrainInterval >= 0, "server.drainInterval", "must not be negative")
+	check(c.Server.ReadinessTimeout >= 0, "server.readinessTimeout", "must not be negative")
+	if err := c.Server.TLS.Validate(); err != nil {
+		check(false, "server.tls", "%v", err)
+	}
+
+	for group, limits := range map[string]ratelimit.GroupConfig{"user": c.RateLimit.User, "admin": c.RateLimit.Admin} {
+		for name, limit := range map[string]ratelimit.Limit{"perToken": limits.PerToken, "perIP": limits.PerIP} {
+			key := "rateLimit." + group + "." + name
+			check(limit.Rate >= 0, key+".rate", "must not be negative")
+			check(limit.Unlimited() || limit.Burst >= 1, key+".burst", "must be at least 1 when rate is set")
+		}
+	}
+
+	check(c.RateLimit.MaxBuckets > 0, "rateLimit.maxBuckets", "must be positive")
+
+	_, err := logging.ParseLevel(c.Log.Level)
+	check(err == nil, "log.level", "%v", err)
+	check(logging.ValidFormat(c.Log.Format), "log.format", "must be json or text, got %q", c.Log.Format)
+
+	check(c.Cache.TTL > 0, "cache.ttl", "must be positive")
+	check(c.Cache.RefreshAhead >= 0, "cache.refreshAhead", "must not be negative")
+	check(c.Cache.RefreshAhead < c.Cache.TTL, "cache.refreshAhead", "must be less than cache.ttl")
+	check(c.Cache.Warmup.TopN >= 0, "cache.warmup.topN", "must not be negative")
+	check(c.Trash.Retention > 0, "trash.retention", "must be positive")
+	check(c.Trash.Interval > 0, "trash.interval", "must be positive")
+	check(c.Impressions.Shards > 0, "impressions.shards", "must be positive")
+	check(c.Impressions.MaxEntries > 0, "impressions.maxEntries", "must be positive")
+
+	check(len(c.Auth.Tokens) != 0, "auth.tokens", "at least one token is required")
+	seen := make(map[string]int, len(c.Auth.Tokens))
+	for i, token := range c.Auth.Tokens {
+		key := fmt.Sprintf("auth.tokens[%d]", i)
+		check(token.Token != "" || token.CertSubject != "", key+".token", "is required unless certSubject is set")
+		if first, ok := seen[token.Token]; ok && token.Token != "" {
+			check(false, key+".token", "duplicates auth.tokens[%d]", first)
+		}
+		seen[token.Token] = i
+		check(token.Subject != "", key+".subject", "is required")
+		switch token.Role {
+		case entities.RoleUser, entities.RoleAdmin, entities.RoleOperator:
+		default:
+			check(false, key+".role", "must be user, admin or operator, got %q", token.Role)
+		}
+		for j, r := range token.Scope.TagRanges {
+			check(r.From <= r.To, fmt.Sprintf("%s.scope.tagRanges[%d]", key, j), "from (%d) must not exceed to (%d)", r.From, r.To)
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// Redacted returns a copy of the config safe to print: database passwords
+// and API tokens are masked.
+func (c Config) Redacted() Config {
+	c.Storage.URL = redactURL(c.Storage.URL)
+	replicas := make([]string, 0, len(c.Storage.Replicas))
+	for _, replica := range c.Storage.Replicas {
+		replicas = append(replicas, redactURL(replica))
+	}
+	c.Storage.Replicas = replicas
+	tokens := make([]TokenConfig, 0, len(c.Auth.Tokens))
+	for _, token := range c.Auth.Tokens {
+		token.Token = redacted
+		tokens = append(tokens, token)
+	}
+	c.Auth.Tokens = tokens
+	return c
+}
+
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		// Unparsable URLs and key=value connection strings may still carry a password.
+		return redacted
+	}
+	if _, hasPassword := u.User.Password(); hasPassword {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+	}
+	return u.String()
+}
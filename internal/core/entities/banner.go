@@ -1,20 +1,104 @@
 package entities
 
-import "time"
+import (
+	"avito-tech-backend/internal/core/targeting"
+	"fmt"
+	"golang.org/x/text/language"
+	"sort"
+	"time"
+)
 
 type Banner struct {
-	ID        int64      `json:"banner_id"`
-	TagIds    []int64    `json:"tag_ids"`
-	FeatureId int64      `json:"feature_id"`
-	Content   string     `json:"content"`
-	IsActive  bool       `json:"is_active"`
-	CreatedAt *time.Time `json:"created_at"`
-	UpdatedAt *time.Time `json:"updated_at"`
-}
-type RawBanner struct {
 	ID        int64   `json:"banner_id"`
 	TagIds    []int64 `json:"tag_ids"`
-	FeatureId string  `json:"feature_id"`
+	FeatureId int64   `json:"feature_id"`
 	Content   string  `json:"content"`
-	IsActive  string  `json:"is_active"`
+	IsActive  bool    `json:"is_active"`
+
+	// LocalizedContent holds content per BCP 47 locale; Content is the
+	// content for DefaultLocale and the fallback when nothing matches.
+	LocalizedContent map[string]string `json:"localized_content,omitempty"`
+	DefaultLocale    string            `json:"default_locale,omitempty"`
+
+	Priority  int64            `json:"priority"`
+	Targeting *targeting.Rules `json:"targeting,omitempty"`
+	// FrequencyCap is how many times a day a single user may see the
+	// banner; zero means no cap.
+	FrequencyCap int64      `json:"frequency_cap"`
+	CreatedAt    *time.Time `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+	Version      int64      `json:"version"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+	Tenant       string     `json:"tenant"`
+
+	Feature *NamedRef  `json:"feature,omitempty"`
+	Tags    []NamedRef `json:"tags,omitempty"`
+}
+type RawBanner struct {
+	ID        int64            `json:"banner_id"`
+	TagIds    *[]int64         `json:"tag_ids"`
+	FeatureId *int64           `json:"feature_id"`
+	Content   *string          `json:"content"`
+	IsActive  *bool            `json:"is_active"`
+	Priority  *int64           `json:"priority"`
+	Targeting *targeting.Rules `json:"targeting"`
+
+	LocalizedContent *map[string]string `json:"localized_content"`
+	DefaultLocale    *string            `json:"default_locale"`
+
+	FrequencyCap *int64 `json:"frequency_cap"`
+
+	// ExpectedVersion makes the update conditional on the stored version.
+	ExpectedVersion *int64 `json:"-"`
+}
+
+// IsEmpty reports whether the update changes nothing.
+func (b *RawBanner) IsEmpty() bool {
+	return b.TagIds == nil && b.FeatureId == nil && b.Content == nil && b.IsActive == nil && b.Priority == nil && b.Targeting == nil &&
+		b.LocalizedContent == nil && b.DefaultLocale == nil && b.FrequencyCap == nil
+}
+
+// NamedRef is a feature or tag reference embedded into admin banner listings.
+type NamedRef struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// ETag identifies the banner revision for conditional requests.
+func (b *Banner) ETag() string {
+	return fmt.Sprintf(`"%d-%d"`, b.ID, b.Version)
+}
+
+// LocalizedETag identifies the banner revision rendered in locale.
+func (b *Banner) LocalizedETag(locale string) string {
+	if locale == "" {
+		return b.ETag()
+	}
+	return fmt.Sprintf(`"%d-%d-%s"`, b.ID, b.Version, locale)
+}
+
+// Localize picks the content best matching the preferred locales using
+// BCP 47 matching and returns it with the locale it is written in. Without
+// a confident match the default content is returned.
+func (b *Banner) Localize(preferred []language.Tag) (content string, locale string) {
+	if len(b.LocalizedContent) == 0 || len(preferred) == 0 {
+		return b.Content, b.DefaultLocale
+	}
+	locales := make([]string, 0, len(b.LocalizedContent)+1)
+	supported := make([]language.Tag, 0, len(b.LocalizedContent)+1)
+	// The first supported tag is what the matcher falls back to.
+	locales = append(locales, b.DefaultLocale)
+	supported = append(supported, language.Make(b.DefaultLocale))
+	for l := range b.LocalizedContent {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales[1:])
+	for _, l := range locales[1:] {
+		supported = append(supported, language.Make(l))
+	}
+	_, index, confidence := language.NewMatcher(supported).Match(preferred...)
+	if confidence == language.No || index == 0 {
+		return b.Content, b.DefaultLocale
+	}
+	return b.LocalizedContent[locales[index]], locales[index]
 }
@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"testing"
+)
+
+func TestBannerLocalize(t *testing.T) {
+	banner := Banner{
+		Content:       `{"title":"Hello"}`,
+		DefaultLocale: "en",
+		LocalizedContent: map[string]string{
+			"de":    `{"title":"Hallo"}`,
+			"pt-BR": `{"title":"Olá"}`,
+		},
+	}
+	cases := []struct {
+		accept  string
+		content string
+		locale  string
+	}{
+		{"", `{"title":"Hello"}`, "en"},
+		{"de-AT", `{"title":"Hallo"}`, "de"},
+		{"pt", `{"title":"Olá"}`, "pt-BR"},
+		{"fr, de;q=0.5", `{"title":"Hallo"}`, "de"},
+		{"ja", `{"title":"Hello"}`, "en"},
+		{"en-GB, de;q=0.9", `{"title":"Hello"}`, "en"},
+	}
+	for _, c := range cases {
+		preferred, _, err := language.ParseAcceptLanguage(c.accept)
+		require.NoError(t, err, c.accept)
+		content, locale := banner.Localize(preferred)
+		require.Equal(t, c.content, content, c.accept)
+		require.Equal(t, c.locale, locale, c.accept)
+	}
+}
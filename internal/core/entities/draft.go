@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+type DraftStatus string
+
+const (
+	DraftStatusDraft    DraftStatus = "draft"
+	DraftStatusPending  DraftStatus = "pending"
+	DraftStatusApproved DraftStatus = "approved"
+	DraftStatusRejected DraftStatus = "rejected"
+)
+
+// BannerDraft is a proposed change to a banner that goes live only after
+// another admin approves it.
+type BannerDraft struct {
+	ID          int64          `json:"draft_id"`
+	BannerId    int64          `json:"banner_id"`
+	Changes     RawBanner      `json:"changes"`
+	BaseVersion int64          `json:"base_version"`
+	Status      DraftStatus    `json:"status"`
+	Author      string         `json:"author"`
+	Reviewer    *string        `json:"reviewer"`
+	CreatedAt   *time.Time     `json:"created_at"`
+	UpdatedAt   *time.Time     `json:"updated_at"`
+	Comments    []DraftComment `json:"comments"`
+	Tenant      string         `json:"tenant"`
+}
+
+type DraftComment struct {
+	ID        int64      `json:"comment_id"`
+	DraftId   int64      `json:"draft_id"`
+	Author    string     `json:"author"`
+	Action    string     `json:"action"`
+	Body      string     `json:"body"`
+	CreatedAt *time.Time `json:"created_at"`
+}
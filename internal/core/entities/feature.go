@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+type Feature struct {
+	ID          int64      `json:"feature_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Owner       string     `json:"owner"`
+	IsArchived  bool       `json:"is_archived"`
+	CreatedAt   *time.Time `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+	Tenant      string     `json:"tenant"`
+}
+type RawFeature struct {
+	ID          int64   `json:"feature_id"`
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Owner       *string `json:"owner"`
+	IsArchived  *bool   `json:"is_archived"`
+}
@@ -0,0 +1,29 @@
+package entities
+
+import "avito-tech-backend/internal/pkg/access"
+
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+	// RoleOperator manages tenants and is an admin of its own tenant.
+	RoleOperator Role = "operator"
+)
+
+// Principal is the caller identified by a request token.
+type Principal struct {
+	Subject string `json:"subject"`
+	Role    Role   `json:"role"`
+	Tenant  string `json:"tenant"`
+	// Scope narrows the banners, features and tags an admin may manage.
+	Scope access.Scope `json:"scope"`
+}
+
+func (p Principal) IsAdmin() bool {
+	return p.Role == RoleAdmin || p.Role == RoleOperator
+}
+
+func (p Principal) IsOperator() bool {
+	return p.Role == RoleOperator
+}
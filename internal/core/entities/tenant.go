@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+// Tenant is a namespace isolating the banners, features and tags of one team.
+type Tenant struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	CreatedAt   *time.Time `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+type RawTenant struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+}
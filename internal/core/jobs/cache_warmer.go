@@ -0,0 +1,134 @@
+package jobs
+
+import (
+	"avito-tech-backend/internal/core/actions"
+	"avito-tech-backend/internal/pkg/cache"
+	"avito-tech-backend/internal/pkg/health"
+	"avito-tech-backend/internal/pkg/tenancy"
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// warmupRetryInterval is how long CacheWarmer waits before retrying a
+// failed warm-up when refreshing is off.
+const warmupRetryInterval = 5 * time.Second
+
+var errNotWarmedUp = errors.New("cache warm-up has not finished")
+
+// CacheWarmer fills the user banner cache on startup and then keeps
+// entries that are being read from expiring.
+//
+// The refresh period follows the live CacheRefreshAhead, so a config reload
+// may change it or set it to zero to pause refreshing. Refreshing off at
+// startup stays off until a restart.
+type CacheWarmer struct {
+	actions    *actions.Actions
+	warmup     cache.WarmupConfig
+	refreshing bool
+	warmedUp   atomic.Bool
+	heartbeat  *health.Heartbeat
+}
+
+// NewCacheWarmer returns new *CacheWarmer.
+func NewCacheWarmer(actions *actions.Actions, config cache.Config) *CacheWarmer {
+	return &CacheWarmer{
+		actions:    actions,
+		warmup:     config.Warmup,
+		refreshing: config.RefreshAhead > 0,
+		// Tolerate one failed refresh before reporting the warmer unhealthy.
+		heartbeat: health.NewHeartbeat(2 * config.RefreshAhead),
+	}
+}
+
+// WarmedUp fails until the startup warm-up has succeeded. It is meant as a
+// readiness check.
+func (w *CacheWarmer) WarmedUp(context.Context) error {
+	if !w.warmedUp.Load() {
+		return errNotWarmedUp
+	}
+	return nil
+}
+
+// Health fails when neither warm-up nor refreshing has succeeded for two
+// refresh periods.
+func (w *CacheWarmer) Health(ctx context.Context) error {
+	return w.heartbeat.Check(ctx)
+}
+
+// Run warms the cache up, retrying until it succeeds, and then refreshes
+// entries expiring within RefreshAhead twice per that period until ctx is
+// done. It works across all tenants.
+func (w *CacheWarmer) Run(ctx context.Context) {
+	ctx = tenancy.WithAllTenants(ctx)
+	interval := w.interval(warmupRetryInterval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if w.warmedUp.Load() && !w.refreshing {
+			return
+		}
+		if err := w.run(ctx); err != nil {
+			w.heartbeat.Fail(err)
+		} else {
+			w.heartbeat.Beat()
+		}
+		if next := w.interval(interval); next != interval {
+			interval = next
+			ticker.Reset(interval)
+			w.heartbeat.SetMaxAge(4 * interval)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// interval returns twice per refresh-ahead period, or current while
+// refreshing is paused.
+func (w *CacheWarmer) interval(current time.Duration) time.Duration {
+	if ahead := w.actions.CacheRefreshAhead(); ahead > 0 {
+		return ahead / 2
+	}
+	return current
+}
+
+// run warms the cache up until that succeeds once and refreshes it after.
+func (w *CacheWarmer) run(ctx context.Context) error {
+	if !w.warmedUp.Load() {
+		return w.warmUp(ctx)
+	}
+	return w.refresh(ctx)
+}
+
+func (w *CacheWarmer) warmUp(ctx context.Context) error {
+	if !w.warmup.Enabled {
+		w.warmedUp.Store(true)
+		return nil
+	}
+	started := time.Now()
+	warmed, err := w.actions.WarmUpCache(ctx, w.warmup.TopN)
+	if err != nil {
+		slog.Error("Error with warming up the banner cache", "error", err)
+		return err
+	}
+	w.warmedUp.Store(true)
+	slog.Info("Warmed up the banner cache", "keys", warmed, "duration", time.Since(started))
+	return nil
+}
+
+func (w *CacheWarmer) refresh(ctx context.Context) error {
+	refreshed, err := w.actions.RefreshCache(ctx)
+	if err != nil {
+		slog.Error("Error with refreshing the banner cache", "error", err)
+		return err
+	}
+	if refreshed > 0 {
+		slog.Debug("Refreshed the banner cache", "keys", refreshed)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package jobs
+
+import (
+	"avito-tech-backend/internal/core/actions"
+	"avito-tech-backend/internal/pkg/health"
+	"avito-tech-backend/internal/pkg/tenancy"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// PurgerConfig represents configuration for Purger.
+type PurgerConfig struct {
+	Retention time.Duration `yaml:"retention"`
+	Interval  time.Duration `yaml:"interval"`
+}
+
+// Purger periodically hard-deletes banners that have been in the trash
+// longer than the retention period, along with impression counters of
+// past days and request counts too old to rank banner pairs.
+type Purger struct {
+	actions   *actions.Actions
+	config    PurgerConfig
+	heartbeat *health.Heartbeat
+}
+
+// NewPurger returns new *Purger.
+func NewPurger(actions *actions.Actions, config PurgerConfig) *Purger {
+	return &Purger{
+		actions: actions,
+		config:  config,
+		// Tolerate one failed run before reporting the purger unhealthy.
+		heartbeat: health.NewHeartbeat(2 * config.Interval),
+	}
+}
+
+// Health fails when purging has not succeeded for two intervals.
+func (p *Purger) Health(ctx context.Context) error {
+	return p.heartbeat.Check(ctx)
+}
+
+// Run purges once per interval until ctx is done. It works across all
+// tenants.
+func (p *Purger) Run(ctx context.Context) {
+	ctx = tenancy.WithAllTenants(ctx)
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+	for {
+		if err := p.purge(ctx); err != nil {
+			p.heartbeat.Fail(err)
+		} else {
+			p.heartbeat.Beat()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Purger) purge(ctx context.Context) error {
+	purged, err := p.actions.PurgeDeletedBanners(ctx, p.config.Retention)
+	if err != nil {
+		slog.Error("Error with purging deleted banners", "error", err)
+		return err
+	}
+	if purged > 0 {
+		slog.Info("Purged deleted banners", "count", purged, "retention", p.config.Retention)
+	}
+	impressions, err := p.actions.PurgeImpressions(ctx)
+	if err != nil {
+		slog.Error("Error with purging impressions", "error", err)
+		return err
+	}
+	if impressions > 0 {
+		slog.Info("Purged impressions", "count", impressions)
+	}
+	requests, err := p.actions.PurgeRequestCounts(ctx)
+	if err != nil {
+		slog.Error("Error with purging banner request counts", "error", err)
+		return err
+	}
+	if requests > 0 {
+		slog.Info("Purged banner request counts", "count", requests)
+	}
+	return nil
+}
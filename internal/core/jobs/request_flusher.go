@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"avito-tech-backend/internal/core/actions"
+	"avito-tech-backend/internal/pkg/health"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// requestFlushInterval is how often RequestFlusher stores counts. Counts of
+// the last interval are lost on shutdown, which barely moves the ranking.
+const requestFlushInterval = time.Minute
+
+// RequestFlusher periodically stores the user banner requests counted in
+// memory, which rank pairs for a top-N warm-up on the next start.
+type RequestFlusher struct {
+	actions   *actions.Actions
+	heartbeat *health.Heartbeat
+}
+
+// NewRequestFlusher returns new *RequestFlusher.
+func NewRequestFlusher(actions *actions.Actions) *RequestFlusher {
+	return &RequestFlusher{
+		actions: actions,
+		// Tolerate one failed flush before reporting the flusher unhealthy.
+		heartbeat: health.NewHeartbeat(2 * requestFlushInterval),
+	}
+}
+
+// Health fails when flushing has not succeeded for two intervals.
+func (f *RequestFlusher) Health(ctx context.Context) error {
+	return f.heartbeat.Check(ctx)
+}
+
+// Run flushes once per interval until ctx is done.
+func (f *RequestFlusher) Run(ctx context.Context) {
+	ticker := time.NewTicker(requestFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := f.flush(ctx); err != nil {
+			f.heartbeat.Fail(err)
+		} else {
+			f.heartbeat.Beat()
+		}
+	}
+}
+
+func (f *RequestFlusher) flush(ctx context.Context) error {
+	flushed, err := f.actions.FlushRequestCounts(ctx)
+	if err != nil {
+		slog.Error("Error with flushing banner request counts", "error", err)
+		return err
+	}
+	if flushed > 0 {
+		slog.Debug("Flushed banner request counts", "keys", flushed)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"avito-tech-backend/internal/core/actions"
+	"avito-tech-backend/internal/pkg/impressions"
 	"avito-tech-backend/internal/storage"
 	"context"
 )
@@ -22,6 +23,10 @@ func NewRepository(ctx context.Context, cfg *Config) (*Repository, error) {
 	if err != nil {
 		return nil, err
 	}
-	r.Actions = actions.NewActions(r.Storage)
+	var backing impressions.Store
+	if cfg.Impressions.Persist {
+		backing = &r.Storage.Impressions
+	}
+	r.Actions = actions.NewActions(r.Storage, cfg.Cache, impressions.NewMemoryStore(cfg.Impressions, backing))
 	return r, nil
 }
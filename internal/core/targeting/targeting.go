@@ -0,0 +1,139 @@
+// Package targeting decides whether a banner is shown to a client based on
+// the client's platform, app version, locale and country. It is independent
+// of storage so that rules can be evaluated on cached banners.
+package targeting
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rules are the optional targeting predicates of a banner. Every non-empty
+// predicate must hold for the banner to match; empty predicates match any
+// client.
+type Rules struct {
+	Platforms   []string       `json:"platforms,omitempty"`
+	AppVersions []VersionRange `json:"app_versions,omitempty"`
+	Locales     []string       `json:"locales,omitempty"`
+	Countries   []string       `json:"countries,omitempty"`
+}
+
+// VersionRange matches versions in [Min, Max). Either bound may be empty.
+type VersionRange struct {
+	Min string `json:"min,omitempty"`
+	Max string `json:"max,omitempty"`
+}
+
+// Context describes the client a banner is selected for. Empty fields are
+// unknown and never satisfy a predicate that constrains them.
+type Context struct {
+	Platform   string
+	AppVersion string
+	Locale     string
+	Country    string
+}
+
+// Validate checks that version bounds are parseable semver.
+func (r *Rules) Validate() error {
+	if r == nil {
+		return nil
+	}
+	for _, vr := range r.AppVersions {
+		for _, bound := range []string{vr.Min, vr.Max} {
+			if bound == "" {
+				continue
+			}
+			if _, err := ParseVersion(bound); err != nil {
+				return err
+			}
+		}
+		if vr.Min == "" && vr.Max == "" {
+			return fmt.Errorf("app version range must have a min or a max")
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the rules accept the client. Nil rules match everyone.
+func (r *Rules) Matches(c Context) bool {
+	if r == nil {
+		return true
+	}
+	if len(r.Platforms) != 0 && !containsFold(r.Platforms, c.Platform) {
+		return false
+	}
+	if len(r.Countries) != 0 && !containsFold(r.Countries, c.Country) {
+		return false
+	}
+	if len(r.Locales) != 0 && !matchesLocale(r.Locales, c.Locale) {
+		return false
+	}
+	if len(r.AppVersions) != 0 && !matchesVersion(r.AppVersions, c.AppVersion) {
+		return false
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesLocale accepts the locale itself or any of its more specific
+// variants: a rule for "en" matches "en-US", a rule for "en-US" does not
+// match "en".
+func matchesLocale(rules []string, locale string) bool {
+	if locale == "" {
+		return false
+	}
+	locale = strings.ReplaceAll(locale, "_", "-")
+	for _, rule := range rules {
+		rule = strings.ReplaceAll(rule, "_", "-")
+		if strings.EqualFold(rule, locale) {
+			return true
+		}
+		if len(locale) > len(rule) && locale[len(rule)] == '-' && strings.EqualFold(locale[:len(rule)], rule) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesVersion(ranges []VersionRange, version string) bool {
+	if version == "" {
+		return false
+	}
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false
+	}
+	for _, vr := range ranges {
+		if vr.contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (vr VersionRange) contains(v Version) bool {
+	if vr.Min != "" {
+		lower, err := ParseVersion(vr.Min)
+		if err != nil || v.Compare(lower) < 0 {
+			return false
+		}
+	}
+	if vr.Max != "" {
+		upper, err := ParseVersion(vr.Max)
+		if err != nil || v.Compare(upper) >= 0 {
+			return false
+		}
+	}
+	return true
+}
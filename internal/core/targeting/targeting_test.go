@@ -0,0 +1,85 @@
+package targeting
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestVersionCompare(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2", "1.2.0", 0},
+		{"1.10.0", "1.9.9", 1},
+		{"2.0.0", "10.0.0", -1},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-beta.11", "1.0.0-beta.2", 1},
+		{"1.0.0-rc.1", "1.0.0-rc.1.1", -1},
+		{"1.0.0+build.5", "1.0.0", 0},
+	}
+	for _, c := range cases {
+		a, err := ParseVersion(c.a)
+		require.NoError(t, err, c.a)
+		b, err := ParseVersion(c.b)
+		require.NoError(t, err, c.b)
+		require.Equalf(t, c.want, a.Compare(b), "%s vs %s", c.a, c.b)
+	}
+}
+
+func TestParseVersionInvalid(t *testing.T) {
+	for _, s := range []string{"", "1.2.3.4", "a.b", "1.-2", "1.0.0-"} {
+		_, err := ParseVersion(s)
+		require.Errorf(t, err, "%q", s)
+	}
+}
+
+func TestRulesMatches(t *testing.T) {
+	rules := &Rules{
+		Platforms:   []string{"ios", "android"},
+		AppVersions: []VersionRange{{Min: "2.0.0", Max: "3.0.0"}, {Min: "4.1.0"}},
+		Locales:     []string{"en", "ru-RU"},
+		Countries:   []string{"RU", "KZ"},
+	}
+	match := Context{Platform: "iOS", AppVersion: "2.5.1", Locale: "en-GB", Country: "ru"}
+
+	cases := []struct {
+		name   string
+		modify func(c *Context)
+		want   bool
+	}{
+		{"all predicates hold", func(c *Context) {}, true},
+		{"platform outside set", func(c *Context) { c.Platform = "web" }, false},
+		{"unknown platform", func(c *Context) { c.Platform = "" }, false},
+		{"version below range", func(c *Context) { c.AppVersion = "1.9.9" }, false},
+		{"version at exclusive max", func(c *Context) { c.AppVersion = "3.0.0" }, false},
+		{"version in open range", func(c *Context) { c.AppVersion = "5.0.0" }, true},
+		{"prerelease before min", func(c *Context) { c.AppVersion = "4.1.0-beta" }, false},
+		{"malformed version", func(c *Context) { c.AppVersion = "latest" }, false},
+		{"exact regional locale", func(c *Context) { c.Locale = "ru_RU" }, true},
+		{"language of regional rule", func(c *Context) { c.Locale = "ru" }, false},
+		{"language prefix is not a subtag", func(c *Context) { c.Locale = "eng" }, false},
+		{"country outside set", func(c *Context) { c.Country = "US" }, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := match
+			c.modify(&ctx)
+			require.Equal(t, c.want, rules.Matches(ctx))
+		})
+	}
+}
+
+func TestEmptyRulesMatchEveryone(t *testing.T) {
+	var rules *Rules
+	require.True(t, rules.Matches(Context{}))
+	require.True(t, (&Rules{}).Matches(Context{}))
+}
+
+func TestRulesValidate(t *testing.T) {
+	require.NoError(t, (&Rules{AppVersions: []VersionRange{{Min: "1.0"}}}).Validate())
+	require.Error(t, (&Rules{AppVersions: []VersionRange{{Max: "x"}}}).Validate())
+	require.Error(t, (&Rules{AppVersions: []VersionRange{{}}}).Validate())
+}
@@ -0,0 +1,53 @@
+package http_server
+
+import (
+	"avito-tech-backend/internal/core/errs"
+	"avito-tech-backend/internal/pkg/logging"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+var errInternal = &errs.Error{Kind: errs.KindInternal, Code: "internal", Message: "internal server error"}
+
+// statusOf maps a domain error kind to an HTTP status code.
+func statusOf(kind errs.Kind) int {
+	switch kind {
+	case errs.KindNotFound:
+		return http.StatusNotFound
+	case errs.KindConflict:
+		return http.StatusConflict
+	case errs.KindValidation:
+		return http.StatusBadRequest
+	case errs.KindForbidden:
+		return http.StatusForbidden
+	case errs.KindUnauthorized:
+		return http.StatusUnauthorized
+	case errs.KindRateLimited:
+		return http.StatusTooManyRequests
+	case errs.KindPreconditionFailed:
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// abortWithError writes err as the JSON error body. Errors that are not
+// domain errors are logged and replaced with a generic internal error, so
+// database and driver messages never reach the client.
+func abortWithError(ctx *gin.Context, err error) {
+	_ = ctx.Error(err)
+	e, ok := errs.As(err)
+	if !ok {
+		logging.FromContext(ctx).Error("Internal error", "error", err, "route", ctx.FullPath())
+		e = errInternal
+	}
+	body := gin.H{
+		"error":      e.Message,
+		"code":       e.Code,
+		"request_id": ctx.GetString(requestIdKey),
+	}
+	if len(e.Details) != 0 {
+		body["details"] = e.Details
+	}
+	ctx.AbortWithStatusJSON(statusOf(e.Kind), body)
+}
@@ -0,0 +1,36 @@
+package http_server
+
+import (
+	"avito-tech-backend/internal/core"
+	"avito-tech-backend/internal/pkg/web"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestErrorsDoNotLeak pins one policy for handlers and /ready: errors that
+// are not domain errors are logged, and clients get a fixed message.
+func TestErrorsDoNotLeak(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	leak := errors.New(`dial tcp 10.0.0.5:5432: password authentication failed for user "banners"`)
+
+	app := &App{Repository: &core.Repository{Config: &core.Config{}}}
+	app.initRoutes()
+	app.Router.GET("/broken", app.mappedHandler(func(*gin.Context, *core.Repository) error {
+		return leak
+	}))
+	app.Server = web.NewServer(web.ServerConfig{}, app.Router)
+	app.Server.AddReadinessCheck("postgres", func(context.Context) error { return leak })
+
+	for path, status := range map[string]int{"/broken": http.StatusInternalServerError, "/ready": http.StatusServiceUnavailable} {
+		rec := httptest.NewRecorder()
+		app.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, status, rec.Code, path)
+		require.NotContains(t, rec.Body.String(), "10.0.0.5", path)
+		require.NotContains(t, rec.Body.String(), "password", path)
+	}
+}
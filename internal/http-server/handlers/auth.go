@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"avito-tech-backend/internal/core/entities"
+	"github.com/gin-gonic/gin"
+)
+
+// PrincipalKey is the gin context key the auth middleware stores the caller under.
+const PrincipalKey = "principal"
+
+func currentPrincipal(ctx *gin.Context) entities.Principal {
+	if principal, ok := ctx.Get(PrincipalKey); ok {
+		return principal.(entities.Principal)
+	}
+	return entities.Principal{}
+}
@@ -3,62 +3,184 @@ package handlers
 import (
 	"avito-tech-backend/internal/core"
 	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/core/targeting"
+	"fmt"
 	"github.com/gin-gonic/gin"
-	"log/slog"
+	"golang.org/x/text/language"
 	"net/http"
-	"strconv"
 )
 
+// clientParams are the query parameters banner targeting is evaluated against.
+type clientParams struct {
+	Platform   string `form:"platform"`
+	AppVersion string `form:"app_version"`
+	Locale     string `form:"locale"`
+	Country    string `form:"country"`
+}
+
+// targeting describes the client for rule evaluation. Without an explicit
+// locale parameter the most preferred Accept-Language tag is used.
+func (p clientParams) targeting(preferred []language.Tag) (targeting.Context, error) {
+	if p.AppVersion != "" {
+		if _, err := targeting.ParseVersion(p.AppVersion); err != nil {
+			return targeting.Context{}, errInvalidRequest.WithDetail("fields", map[string]string{"app_version": "semver"})
+		}
+	}
+	locale := p.Locale
+	if locale == "" && len(preferred) != 0 {
+		locale = preferred[0].String()
+	}
+	return targeting.Context{
+		Platform:   p.Platform,
+		AppVersion: p.AppVersion,
+		Locale:     locale,
+		Country:    p.Country,
+	}, nil
+}
+
 func GetUserBanner(ctx *gin.Context, r *core.Repository) error {
 	var queryParams = struct {
-		TagId          int64 `form:"tag_id" required:"true"`
-		FeatureId      int64 `form:"feature_id" required:"true"`
-		UseLastVersion bool  `form:"use_last_version"`
+		TagId          int64  `form:"tag_id" binding:"required"`
+		FeatureId      int64  `form:"feature_id" binding:"required"`
+		UseLastVersion bool   `form:"use_last_version"`
+		UserId         string `form:"user_id"`
+		clientParams
 	}{
 		UseLastVersion: false,
 	}
-	if err := ctx.BindQuery(&queryParams); err != nil {
-		slog.Debug("Error with getting user banner: %s", err)
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
-		return nil
+	if err := ctx.ShouldBindQuery(&queryParams); err != nil {
+		return invalidRequest(err)
+	}
+	preferred, err := preferredLocales(ctx, queryParams.Locale)
+	if err != nil {
+		return err
+	}
+	client, err := queryParams.targeting(preferred)
+	if err != nil {
+		return err
+	}
+	// A conditional request for the banner the client already has is not a
+	// new impression.
+	revalidated := func(banner *entities.Banner) bool {
+		_, locale := banner.Localize(preferred)
+		return etagListContains(ctx.GetHeader("If-None-Match"), banner.LocalizedETag(locale))
 	}
-	banner, err := r.Actions.GetUserBanner(ctx, queryParams.TagId, queryParams.FeatureId, queryParams.UseLastVersion)
+	banner, err := r.Actions.GetUserBanner(ctx, queryParams.TagId, queryParams.FeatureId, client, queryParams.UserId, queryParams.UseLastVersion, revalidated)
 	if err != nil {
 		return err
 	}
-	if banner == nil {
-		slog.Debug("Error with getting user banner: %s", err)
-		ctx.Status(http.StatusNotFound)
+	content, locale := banner.Localize(preferred)
+	setUserCacheControl(ctx, r, queryParams.UseLastVersion)
+	setContentLanguage(ctx, locale)
+	if notModified(ctx, banner.LocalizedETag(locale)) {
 		return nil
 	}
 	ctx.JSON(http.StatusOK, gin.H{
-		"content": banner.Content,
+		"content": content,
 	})
 	return nil
 }
 
-func CreateBanner(ctx *gin.Context, r *core.Repository) error {
-	var Banner struct {
-		TagIds    []int64 `json:"tag_ids" required:"true"`
-		FeatureId int64   `json:"feature_id" required:"true"`
-		Content   string  `json:"content" required:"true"`
-		IsActive  bool    `json:"is_active" required:"true"`
-	}
-	if err := ctx.BindJSON(&Banner); err != nil {
-		slog.Debug("Error with creating banner: %s", err)
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
+func GetUserBanners(ctx *gin.Context, r *core.Repository) error {
+	var queryParams = struct {
+		TagId          int64  `form:"tag_id" binding:"required"`
+		FeatureId      int64  `form:"feature_id" binding:"required"`
+		Limit          int    `form:"limit" binding:"min=1,max=100"`
+		UseLastVersion bool   `form:"use_last_version"`
+		UserId         string `form:"user_id"`
+		clientParams
+	}{
+		Limit:          10,
+		UseLastVersion: false,
+	}
+	if err := ctx.ShouldBindQuery(&queryParams); err != nil {
+		return invalidRequest(err)
+	}
+	preferred, err := preferredLocales(ctx, queryParams.Locale)
+	if err != nil {
+		return err
+	}
+	client, err := queryParams.targeting(preferred)
+	if err != nil {
+		return err
+	}
+	banners, err := r.Actions.GetUserBanners(ctx, queryParams.TagId, queryParams.FeatureId, client, queryParams.UserId, queryParams.Limit, queryParams.UseLastVersion)
+	if err != nil {
+		return err
+	}
+	setUserCacheControl(ctx, r, queryParams.UseLastVersion)
+	ctx.Header("Vary", "Accept-Language")
+	response := make([]gin.H, 0, len(banners))
+	for _, banner := range banners {
+		content, locale := banner.Localize(preferred)
+		response = append(response, gin.H{
+			"banner_id": banner.ID,
+			"content":   content,
+			"locale":    locale,
+			"priority":  banner.Priority,
 		})
+	}
+	ctx.JSON(http.StatusOK, response)
+	return nil
+}
+
+// setUserCacheControl lets clients reuse user banners for as long as the
+// server itself may serve them stale.
+func setUserCacheControl(ctx *gin.Context, r *core.Repository, useLastVersion bool) {
+	if useLastVersion {
+		ctx.Header("Cache-Control", "no-cache")
+	} else {
+		ctx.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(r.Actions.CacheTTL().Seconds())))
+	}
+}
+
+func GetBanner(ctx *gin.Context, r *core.Repository) error {
+	bannerId, err := pathId(ctx)
+	if err != nil {
+		return err
+	}
+	banner, err := r.Actions.GetBanner(ctx, bannerId)
+	if err != nil {
+		return err
+	}
+	ctx.Header("Cache-Control", "private, no-cache")
+	if notModified(ctx, banner.ETag()) {
 		return nil
 	}
+	ctx.JSON(http.StatusOK, banner)
+	return nil
+}
+
+func CreateBanner(ctx *gin.Context, r *core.Repository) error {
+	var Banner struct {
+		TagIds    []int64          `json:"tag_ids" binding:"required"`
+		FeatureId int64            `json:"feature_id" binding:"required"`
+		Content   string           `json:"content" binding:"required"`
+		IsActive  bool             `json:"is_active"`
+		Priority  int64            `json:"priority"`
+		Targeting *targeting.Rules `json:"targeting"`
+
+		LocalizedContent map[string]string `json:"localized_content"`
+		DefaultLocale    string            `json:"default_locale"`
+
+		FrequencyCap int64 `json:"frequency_cap" binding:"min=0"`
+	}
+	if err := ctx.ShouldBindJSON(&Banner); err != nil {
+		return invalidRequest(err)
+	}
 
 	banner, err := r.Actions.CreateBanner(ctx, entities.Banner{
 		TagIds:    Banner.TagIds,
 		FeatureId: Banner.FeatureId,
 		Content:   Banner.Content,
 		IsActive:  Banner.IsActive,
+		Priority:  Banner.Priority,
+		Targeting: Banner.Targeting,
+
+		LocalizedContent: Banner.LocalizedContent,
+		DefaultLocale:    Banner.DefaultLocale,
+
+		FrequencyCap: Banner.FrequencyCap,
 	})
 	if err != nil {
 		return err
@@ -70,78 +192,68 @@ func CreateBanner(ctx *gin.Context, r *core.Repository) error {
 }
 
 func UpdateBanner(ctx *gin.Context, r *core.Repository) error {
-	bannerId, err := strconv.ParseInt(ctx.Param("banner_id"), 10, 0)
+	if r.Config.Drafts.RequireReview {
+		return errReviewRequired
+	}
+	bannerId, err := pathId(ctx)
 	if err != nil {
-		slog.Debug("Error with updating banner: %s", err)
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
-		return nil
+		return err
 	}
 	var Banner struct {
-		TagIds    string `json:"tag_ids"`
-		FeatureId string `json:"feature_id"`
-		Content   string `json:"content"`
-		IsActive  string `json:"is_active"`
-	}
-	if err := ctx.BindJSON(&Banner); err != nil {
-		slog.Debug("Error with updating banner: %s", err)
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
-		return nil
-	}
-	if Banner.TagIds == "null" && Banner.FeatureId == "null" && Banner.Content == "null" && Banner.IsActive == "null" {
-		slog.Debug("Error with updating banner: you must pass at least one parameter")
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "Error with updating banner: you must pass at least one parameter",
-		})
-		return nil
+		TagIds    *[]int64         `json:"tag_ids"`
+		FeatureId *int64           `json:"feature_id"`
+		Content   *string          `json:"content"`
+		IsActive  *bool            `json:"is_active"`
+		Priority  *int64           `json:"priority"`
+		Targeting *targeting.Rules `json:"targeting"`
+
+		LocalizedContent *map[string]string `json:"localized_content"`
+		DefaultLocale    *string            `json:"default_locale"`
+
+		FrequencyCap *int64 `json:"frequency_cap" binding:"omitempty,min=0"`
 	}
-	var TagIdsStruct struct {
-		TagIds []int64 `json:"tag_ids"`
+	if err := ctx.ShouldBindJSON(&Banner); err != nil {
+		return invalidRequest(err)
 	}
-	if Banner.TagIds != "null" {
-		if err := ctx.BindJSON(&TagIdsStruct); err != nil {
-
-		}
-	} else {
-		TagIdsStruct.TagIds = nil
+	expectedVersion, err := ifMatchVersion(ctx, bannerId)
+	if err != nil {
+		return err
 	}
-	banner, err := r.Actions.UpdateBanner(ctx, entities.RawBanner{
+	request := entities.RawBanner{
 		ID:        bannerId,
-		TagIds:    TagIdsStruct.TagIds,
+		TagIds:    Banner.TagIds,
 		FeatureId: Banner.FeatureId,
 		Content:   Banner.Content,
 		IsActive:  Banner.IsActive,
-	})
+		Priority:  Banner.Priority,
+		Targeting: Banner.Targeting,
+
+		LocalizedContent: Banner.LocalizedContent,
+		DefaultLocale:    Banner.DefaultLocale,
+
+		FrequencyCap: Banner.FrequencyCap,
+
+		ExpectedVersion: expectedVersion,
+	}
+	if request.IsEmpty() {
+		return errEmptyUpdate
+	}
+	banner, err := r.Actions.UpdateBanner(ctx, request)
 	if err != nil {
 		return err
 	}
-	if banner == nil {
-		ctx.Status(http.StatusNotFound)
-		return nil
-	}
+	ctx.Header("ETag", banner.ETag())
 	ctx.JSON(http.StatusOK, gin.H{})
 	return nil
 }
 
 func DeleteBanner(ctx *gin.Context, r *core.Repository) error {
-	bannerId, err := strconv.ParseInt(ctx.Param("banner_id"), 10, 0)
-	if err != nil {
-		slog.Debug("Error with deleting banner: %s", err)
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
-		return nil
-	}
-	banner, err := r.Actions.DeleteBanner(ctx, bannerId)
+	bannerId, err := pathId(ctx)
 	if err != nil {
 		return err
 	}
-	if banner == nil {
-		ctx.Status(http.StatusNotFound)
-		return nil
+	if _, err := r.Actions.DeleteBanner(ctx, bannerId); err != nil {
+		return err
 	}
 	ctx.Status(http.StatusNoContent)
 	return nil
@@ -149,25 +261,54 @@ func DeleteBanner(ctx *gin.Context, r *core.Repository) error {
 
 func GetBanners(ctx *gin.Context, r *core.Repository) error {
 	var queryParams = struct {
-		TagId     int64  `form:"tag_id"`
-		FeatureId int64  `form:"feature_id"`
-		Limit     uint64 `form:"limit"`
-		Offset    uint64 `form:"offset"`
+		TagId      int64  `form:"tag_id"`
+		FeatureId  int64  `form:"feature_id"`
+		Limit      uint64 `form:"limit"`
+		Offset     uint64 `form:"offset"`
+		EmbedNames bool   `form:"embed_names"`
 	}{
 		Limit:  1,
 		Offset: 0,
 	}
-	if err := ctx.BindQuery(&queryParams); err != nil {
-		slog.Debug("Error with getting banners: %s", err)
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
-		return nil
+	if err := ctx.ShouldBindQuery(&queryParams); err != nil {
+		return invalidRequest(err)
 	}
-	banners, err := r.Actions.GetBanners(ctx, queryParams.TagId, queryParams.FeatureId, queryParams.Limit, queryParams.Offset)
+	banners, err := r.Actions.GetBanners(ctx, queryParams.TagId, queryParams.FeatureId, queryParams.Limit, queryParams.Offset, queryParams.EmbedNames)
 	if err != nil {
 		return err
 	}
 	ctx.JSON(http.StatusOK, banners)
 	return nil
 }
+
+func GetDeletedBanners(ctx *gin.Context, r *core.Repository) error {
+	var queryParams = struct {
+		Limit  uint64 `form:"limit"`
+		Offset uint64 `form:"offset"`
+	}{
+		Limit:  100,
+		Offset: 0,
+	}
+	if err := ctx.ShouldBindQuery(&queryParams); err != nil {
+		return invalidRequest(err)
+	}
+	banners, err := r.Actions.GetDeletedBanners(ctx, queryParams.Limit, queryParams.Offset)
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusOK, banners)
+	return nil
+}
+
+func RestoreBanner(ctx *gin.Context, r *core.Repository) error {
+	bannerId, err := pathId(ctx)
+	if err != nil {
+		return err
+	}
+	banner, err := r.Actions.RestoreBanner(ctx, bannerId)
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusOK, banner)
+	return nil
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"avito-tech-backend/internal/core"
+	"avito-tech-backend/internal/core/actions"
+	"avito-tech-backend/internal/core/errs"
+	"avito-tech-backend/internal/pkg/cache"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUserCacheControlFollowsReload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &core.Config{Cache: cache.Config{TTL: 5 * time.Minute}}
+	r := &core.Repository{Config: cfg, Actions: actions.NewActions(nil, cfg.Cache, nil)}
+	maxAge := func() string {
+		rec := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rec)
+		setUserCacheControl(ctx, r, false)
+		return rec.Header().Get("Cache-Control")
+	}
+
+	require.Equal(t, "private, max-age=300", maxAge())
+	r.Actions.SetCacheConfig(cache.Config{TTL: 20 * time.Second})
+	require.Equal(t, "private, max-age=20", maxAge(), "clients must not keep banners longer than the server does")
+}
+
+func TestUpdateBannerRequiresReview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &core.Config{Drafts: core.DraftsConfig{RequireReview: true}}
+	r := &core.Repository{Config: cfg, Actions: actions.NewActions(nil, cfg.Cache, nil)}
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+	ctx.Request = httptest.NewRequest(http.MethodPatch, "/banner/1", strings.NewReader(`{"content":"{}"}`))
+
+	e, ok := errs.As(UpdateBanner(ctx, r))
+	require.True(t, ok)
+	require.Equal(t, "review_required", e.Code, "direct updates must not bypass the draft review")
+}
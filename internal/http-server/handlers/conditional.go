@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"avito-tech-backend/internal/core/errs"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var errInvalidIfMatch = errs.Validation("invalid_if_match", "If-Match must be the banner ETag or its version number")
+
+// notModified sets the ETag header and writes 304 Not Modified when the
+// request's If-None-Match already names etag.
+func notModified(ctx *gin.Context, etag string) bool {
+	ctx.Header("ETag", etag)
+	if etagListContains(ctx.GetHeader("If-None-Match"), etag) {
+		ctx.Status(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// etagListContains reports whether a comma-separated If-None-Match/If-Match
+// value matches etag using weak comparison.
+func etagListContains(header string, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion parses If-Match as either the banner ETag or a bare version
+// number. A missing header or "*" yields nil: the update is unconditional.
+func ifMatchVersion(ctx *gin.Context, bannerId int64) (*int64, error) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+	value := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	if id, version, ok := strings.Cut(value, "-"); ok {
+		if id != strconv.FormatInt(bannerId, 10) {
+			return nil, errInvalidIfMatch.WithDetail("if_match", header)
+		}
+		value = version
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, errInvalidIfMatch.WithDetail("if_match", header)
+	}
+	return &version, nil
+}
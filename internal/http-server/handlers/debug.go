@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"avito-tech-backend/internal/core"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// GetCacheDebug dumps user banner cache counters and keys for diagnosis.
+func GetCacheDebug(ctx *gin.Context, r *core.Repository) error {
+	var queryParams = struct {
+		Limit int `form:"limit" binding:"min=0"`
+	}{
+		Limit: 1000,
+	}
+	if err := ctx.ShouldBindQuery(&queryParams); err != nil {
+		return invalidRequest(err)
+	}
+	stats := r.Actions.GetCacheStats(queryParams.Limit)
+	hitRatio := 0.0
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		hitRatio = float64(stats.Hits) / float64(lookups)
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"ttl":       stats.TTL.String(),
+		"entries":   stats.Entries,
+		"hits":      stats.Hits,
+		"misses":    stats.Misses,
+		"hit_ratio": hitRatio,
+		"fetches":   stats.Fetches,
+		"coalesced": stats.Coalesced,
+		"keys":      stats.Keys,
+		"truncated": stats.Truncated,
+	})
+	return nil
+}
+
+// GetDatabaseDebug reports how many read replicas currently take reads.
+func GetDatabaseDebug(ctx *gin.Context, r *core.Repository) error {
+	healthy, total := r.Storage.Database.HealthyReplicas()
+	ctx.JSON(http.StatusOK, gin.H{
+		"replicas":         total,
+		"healthy_replicas": healthy,
+	})
+	return nil
+}
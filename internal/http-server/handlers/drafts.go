@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"avito-tech-backend/internal/core"
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/core/targeting"
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type draftCommentRequest struct {
+	Comment string `json:"comment"`
+}
+
+type draftAction func(ctx context.Context, draftId int64, actor string, comment string) (*entities.BannerDraft, error)
+
+func GetDrafts(ctx *gin.Context, r *core.Repository) error {
+	bannerId, err := pathId(ctx)
+	if err != nil {
+		return err
+	}
+	drafts, err := r.Actions.GetDrafts(ctx, bannerId)
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusOK, drafts)
+	return nil
+}
+
+func CreateDraft(ctx *gin.Context, r *core.Repository) error {
+	bannerId, err := pathId(ctx)
+	if err != nil {
+		return err
+	}
+	var Draft struct {
+		TagIds    *[]int64         `json:"tag_ids"`
+		FeatureId *int64           `json:"feature_id"`
+		Content   *string          `json:"content"`
+		IsActive  *bool            `json:"is_active"`
+		Priority  *int64           `json:"priority"`
+		Targeting *targeting.Rules `json:"targeting"`
+		Comment   string           `json:"comment"`
+
+		LocalizedContent *map[string]string `json:"localized_content"`
+		DefaultLocale    *string            `json:"default_locale"`
+
+		FrequencyCap *int64 `json:"frequency_cap" binding:"omitempty,min=0"`
+	}
+	if err := ctx.ShouldBindJSON(&Draft); err != nil {
+		return invalidRequest(err)
+	}
+	draft, err := r.Actions.CreateDraft(ctx, entities.RawBanner{
+		ID:        bannerId,
+		TagIds:    Draft.TagIds,
+		FeatureId: Draft.FeatureId,
+		Content:   Draft.Content,
+		IsActive:  Draft.IsActive,
+		Priority:  Draft.Priority,
+		Targeting: Draft.Targeting,
+
+		LocalizedContent: Draft.LocalizedContent,
+		DefaultLocale:    Draft.DefaultLocale,
+
+		FrequencyCap: Draft.FrequencyCap,
+	}, currentPrincipal(ctx).Subject, Draft.Comment)
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusCreated, draft)
+	return nil
+}
+
+func SubmitDraft(ctx *gin.Context, r *core.Repository) error {
+	return reviewDraft(ctx, r.Actions.SubmitDraft)
+}
+
+func ApproveDraft(ctx *gin.Context, r *core.Repository) error {
+	return reviewDraft(ctx, r.Actions.ApproveDraft)
+}
+
+func RejectDraft(ctx *gin.Context, r *core.Repository) error {
+	return reviewDraft(ctx, r.Actions.RejectDraft)
+}
+
+func reviewDraft(ctx *gin.Context, action draftAction) error {
+	draftId, err := pathId(ctx)
+	if err != nil {
+		return err
+	}
+	var request draftCommentRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			return invalidRequest(err)
+		}
+	}
+	draft, err := action(ctx, draftId, currentPrincipal(ctx).Subject, request.Comment)
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusOK, draft)
+	return nil
+}
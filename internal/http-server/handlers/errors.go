@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"avito-tech-backend/internal/core/errs"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	errInvalidRequest = errs.Validation("invalid_request", "request is malformed")
+	errInvalidId      = errs.Validation("invalid_id", "path id must be an integer")
+	errEmptyUpdate    = errs.Validation("empty_update", "you must pass at least one parameter")
+	errReviewRequired = errs.Forbidden("review_required", "banner changes must be submitted as a draft for review")
+)
+
+func init() {
+	// Report fields by the names clients send rather than Go field names.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(paramName)
+	}
+}
+
+func paramName(field reflect.StructField) string {
+	for _, key := range []string{"form", "json"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// invalidRequest converts a gin binding error into a validation error that
+// lists the offending fields. Other binding errors get a generic reason, as
+// their messages name Go types and internal fields.
+func invalidRequest(err error) error {
+	var validationErrors validator.ValidationErrors
+	var typeError *json.UnmarshalTypeError
+	var syntaxError *json.SyntaxError
+	var numError *strconv.NumError
+	switch {
+	case errors.As(err, &validationErrors):
+		fields := make(map[string]string, len(validationErrors))
+		for _, fieldError := range validationErrors {
+			fields[fieldError.Field()] = fieldError.Tag()
+		}
+		return errInvalidRequest.WithDetail("fields", fields)
+	case errors.As(err, &typeError) && typeError.Field != "":
+		return errInvalidRequest.WithDetail("fields", map[string]string{typeError.Field: "type"})
+	case errors.As(err, &syntaxError), errors.Is(err, io.ErrUnexpectedEOF):
+		return errInvalidRequest.WithDetail("reason", "body is not valid JSON")
+	case errors.Is(err, io.EOF):
+		return errInvalidRequest.WithDetail("reason", "body is empty")
+	case errors.As(err, &numError):
+		return errInvalidRequest.WithDetail("reason", "a parameter has an invalid value")
+	default:
+		return errInvalidRequest.WithDetail("reason", "request could not be parsed")
+	}
+}
+
+func pathId(ctx *gin.Context) (int64, error) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 0)
+	if err != nil {
+		return 0, errInvalidId.WithDetail("id", ctx.Param("id"))
+	}
+	return id, nil
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"avito-tech-backend/internal/core/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInvalidRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	type params struct {
+		TagId   int64   `form:"tag_id" binding:"required"`
+		TagIds  []int64 `json:"tag_ids"`
+		Content string  `json:"content,omitempty" binding:"required"`
+	}
+	bind := func(target string, body string) *errs.Error {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		var p params
+		var err error
+		if body == "" {
+			err = ctx.ShouldBindQuery(&p)
+		} else {
+			err = ctx.ShouldBindJSON(&p)
+		}
+		require.Error(t, err)
+		e, ok := errs.As(invalidRequest(err))
+		require.True(t, ok)
+		require.Equal(t, "invalid_request", e.Code)
+		return e
+	}
+
+	e := bind("/", "")
+	require.Equal(t, map[string]string{"tag_id": "required", "content": "required"}, e.Details["fields"])
+
+	e = bind("/?tag_id=abc", "")
+	require.Equal(t, "a parameter has an invalid value", e.Details["reason"])
+
+	e = bind("/", `{"tag_ids": "x"}`)
+	require.Equal(t, map[string]string{"tag_ids": "type"}, e.Details["fields"])
+
+	e = bind("/", `{"content": `)
+	require.Equal(t, "body is not valid JSON", e.Details["reason"])
+}
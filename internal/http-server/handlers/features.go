@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"avito-tech-backend/internal/core"
+	"avito-tech-backend/internal/core/entities"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func GetFeatures(ctx *gin.Context, r *core.Repository) error {
+	var queryParams = struct {
+		IncludeArchived bool   `form:"include_archived"`
+		Limit           uint64 `form:"limit"`
+		Offset          uint64 `form:"offset"`
+	}{
+		Limit:  100,
+		Offset: 0,
+	}
+	if err := ctx.ShouldBindQuery(&queryParams); err != nil {
+		return invalidRequest(err)
+	}
+	features, err := r.Actions.GetFeatures(ctx, queryParams.IncludeArchived, queryParams.Limit, queryParams.Offset)
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusOK, features)
+	return nil
+}
+
+func GetFeature(ctx *gin.Context, r *core.Repository) error {
+	featureId, err := pathId(ctx)
+	if err != nil {
+		return err
+	}
+	feature, err := r.Actions.GetFeature(ctx, featureId)
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusOK, feature)
+	return nil
+}
+
+func CreateFeature(ctx *gin.Context, r *core.Repository) error {
+	var Feature struct {
+		Name        string `json:"name" binding:"required"`
+		Description string `json:"description"`
+		Owner       string `json:"owner"`
+	}
+	if err := ctx.ShouldBindJSON(&Feature); err != nil {
+		return invalidRequest(err)
+	}
+	feature, err := r.Actions.CreateFeature(ctx, entities.Feature{
+		Name:        Feature.Name,
+		Description: Feature.Description,
+		Owner:       Feature.Owner,
+	})
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusCreated, gin.H{
+		"feature_id": feature.ID,
+	})
+	return nil
+}
+
+func UpdateFeature(ctx *gin.Context, r *core.Repository) error {
+	featureId, err := pathId(ctx)
+	if err != nil {
+		return err
+	}
+	var Feature struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+		Owner       *string `json:"owner"`
+		IsArchived  *bool   `json:"is_archived"`
+	}
+	if err := ctx.ShouldBindJSON(&Feature); err != nil {
+		return invalidRequest(err)
+	}
+	if Feature.Name == nil && Feature.Description == nil && Feature.Owner == nil && Feature.IsArchived == nil {
+		return errEmptyUpdate
+	}
+	feature, err := r.Actions.UpdateFeature(ctx, entities.RawFeature{
+		ID:          featureId,
+		Name:        Feature.Name,
+		Description: Feature.Description,
+		Owner:       Feature.Owner,
+		IsArchived:  Feature.IsArchived,
+	})
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusOK, feature)
+	return nil
+}
+
+func DeleteFeature(ctx *gin.Context, r *core.Repository) error {
+	featureId, err := pathId(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := r.Actions.DeleteFeature(ctx, featureId); err != nil {
+		return err
+	}
+	ctx.Status(http.StatusNoContent)
+	return nil
+}
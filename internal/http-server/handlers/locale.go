@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// preferredLocales returns the client's locale preferences: the locale query
+// parameter when given, otherwise the Accept-Language header. A malformed
+// header is ignored rather than failing the request.
+func preferredLocales(ctx *gin.Context, locale string) ([]language.Tag, error) {
+	if locale != "" {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			return nil, errInvalidRequest.WithDetail("fields", map[string]string{"locale": "bcp47"})
+		}
+		return []language.Tag{tag}, nil
+	}
+	tags, _, err := language.ParseAcceptLanguage(ctx.GetHeader("Accept-Language"))
+	if err != nil {
+		return nil, nil
+	}
+	return tags, nil
+}
+
+// setContentLanguage advertises the locale the content was resolved to.
+func setContentLanguage(ctx *gin.Context, locale string) {
+	ctx.Header("Vary", "Accept-Language")
+	if locale != "" {
+		ctx.Header("Content-Language", locale)
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"avito-tech-backend/internal/core"
+	"avito-tech-backend/internal/core/entities"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func GetTags(ctx *gin.Context, r *core.Repository) error {
+	var queryParams = struct {
+		IncludeArchived bool   `form:"include_archived"`
+		Limit           uint64 `form:"limit"`
+		Offset          uint64 `form:"offset"`
+	}{
+		Limit:  100,
+		Offset: 0,
+	}
+	if err := ctx.ShouldBindQuery(&queryParams); err != nil {
+		return invalidRequest(err)
+	}
+	tags, err := r.Actions.GetTags(ctx, queryParams.IncludeArchived, queryParams.Limit, queryParams.Offset)
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusOK, tags)
+	return nil
+}
+
+func GetTag(ctx *gin.Context, r *core.Repository) error {
+	tagId, err := pathId(ctx)
+	if err != nil {
+		return err
+	}
+	tag, err := r.Actions.GetTag(ctx, tagId)
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusOK, tag)
+	return nil
+}
+
+func CreateTag(ctx *gin.Context, r *core.Repository) error {
+	var Tag struct {
+		Name        string `json:"name" binding:"required"`
+		Description string `json:"description"`
+		Owner       string `json:"owner"`
+	}
+	if err := ctx.ShouldBindJSON(&Tag); err != nil {
+		return invalidRequest(err)
+	}
+	tag, err := r.Actions.CreateTag(ctx, entities.Tag{
+		Name:        Tag.Name,
+		Description: Tag.Description,
+		Owner:       Tag.Owner,
+	})
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusCreated, gin.H{
+		"tag_id": tag.ID,
+	})
+	return nil
+}
+
+func UpdateTag(ctx *gin.Context, r *core.Repository) error {
+	tagId, err := pathId(ctx)
+	if err != nil {
+		return err
+	}
+	var Tag struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+		Owner       *string `json:"owner"`
+		IsArchived  *bool   `json:"is_archived"`
+	}
+	if err := ctx.ShouldBindJSON(&Tag); err != nil {
+		return invalidRequest(err)
+	}
+	if Tag.Name == nil && Tag.Description == nil && Tag.Owner == nil && Tag.IsArchived == nil {
+		return errEmptyUpdate
+	}
+	tag, err := r.Actions.UpdateTag(ctx, entities.RawTag{
+		ID:          tagId,
+		Name:        Tag.Name,
+		Description: Tag.Description,
+		Owner:       Tag.Owner,
+		IsArchived:  Tag.IsArchived,
+	})
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusOK, tag)
+	return nil
+}
+
+func DeleteTag(ctx *gin.Context, r *core.Repository) error {
+	tagId, err := pathId(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := r.Actions.DeleteTag(ctx, tagId); err != nil {
+		return err
+	}
+	ctx.Status(http.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"avito-tech-backend/internal/core"
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/core/errs"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+var errTenantHasTokens = errs.Conflict("tenant_has_tokens", "tenant is still named by configured tokens")
+
+func GetTenants(ctx *gin.Context, r *core.Repository) error {
+	var queryParams = struct {
+		Limit  uint64 `form:"limit"`
+		Offset uint64 `form:"offset"`
+	}{
+		Limit:  100,
+		Offset: 0,
+	}
+	if err := ctx.ShouldBindQuery(&queryParams); err != nil {
+		return invalidRequest(err)
+	}
+	tenants, err := r.Actions.GetTenants(ctx, queryParams.Limit, queryParams.Offset)
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusOK, tenants)
+	return nil
+}
+
+func GetTenant(ctx *gin.Context, r *core.Repository) error {
+	tenant, err := r.Actions.GetTenant(ctx, ctx.Param("name"))
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusOK, tenant)
+	return nil
+}
+
+func CreateTenant(ctx *gin.Context, r *core.Repository) error {
+	var Tenant struct {
+		Name        string `json:"name" binding:"required"`
+		Description string `json:"description"`
+	}
+	if err := ctx.ShouldBindJSON(&Tenant); err != nil {
+		return invalidRequest(err)
+	}
+	tenant, err := r.Actions.CreateTenant(ctx, entities.Tenant{
+		Name:        Tenant.Name,
+		Description: Tenant.Description,
+	})
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusCreated, tenant)
+	return nil
+}
+
+func UpdateTenant(ctx *gin.Context, r *core.Repository) error {
+	var Tenant struct {
+		Description *string `json:"description"`
+	}
+	if err := ctx.ShouldBindJSON(&Tenant); err != nil {
+		return invalidRequest(err)
+	}
+	if Tenant.Description == nil {
+		return errEmptyUpdate
+	}
+	tenant, err := r.Actions.UpdateTenant(ctx, entities.RawTenant{
+		Name:        ctx.Param("name"),
+		Description: Tenant.Description,
+	})
+	if err != nil {
+		return err
+	}
+	ctx.JSON(http.StatusOK, tenant)
+	return nil
+}
+
+func DeleteTenant(ctx *gin.Context, r *core.Repository) error {
+	name := ctx.Param("name")
+	if r.Config.Auth.NamesTenant(name) {
+		return errTenantHasTokens.WithDetail("name", name)
+	}
+	if _, err := r.Actions.DeleteTenant(ctx, name); err != nil {
+		return err
+	}
+	ctx.Status(http.StatusNoContent)
+	return nil
+}
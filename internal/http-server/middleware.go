@@ -0,0 +1,165 @@
+package http_server
+
+import (
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/core/errs"
+	"avito-tech-backend/internal/http-server/handlers"
+	"avito-tech-backend/internal/pkg/logging"
+	"avito-tech-backend/internal/pkg/ratelimit"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	requestIdHeader = "X-Request-ID"
+	requestIdKey    = "request_id"
+
+	maxRequestIdLength = 128
+)
+
+// requestContextMiddleware assigns or propagates X-Request-ID, puts a logger
+// tagged with it into the request context and writes one access log line
+// once the request is served.
+func requestContextMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		id := ctx.GetHeader(requestIdHeader)
+		if !validRequestId(id) {
+			id = newRequestId()
+		}
+		ctx.Set(requestIdKey, id)
+		ctx.Header(requestIdHeader, id)
+
+		logger := slog.Default().With("request_id", id)
+		ctx.Request = ctx.Request.WithContext(logging.WithLogger(ctx.Request.Context(), logger))
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+		logger.LogAttrs(ctx.Request.Context(), level, "access",
+			slog.String("method", ctx.Request.Method),
+			slog.String("route", ctx.FullPath()),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("subject", principalSubject(ctx)),
+			slog.String("client_ip", ctx.ClientIP()),
+			slog.Int("size", ctx.Writer.Size()),
+		)
+	}
+}
+
+func principalSubject(ctx *gin.Context) string {
+	if principal, ok := ctx.Get(handlers.PrincipalKey); ok {
+		return principal.(entities.Principal).Subject
+	}
+	return ""
+}
+
+func validRequestId(id string) bool {
+	if id == "" || len(id) > maxRequestIdLength {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestId() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+var errRateLimited = errs.RateLimited("rate_limited", "rate limit exceeded")
+
+// rateLimitResultKey keeps the most restrictive bucket of earlier rate
+// limit stages of the request.
+const rateLimitResultKey = "rate_limit_result"
+
+type rateLimitCheck struct {
+	key   string
+	limit ratelimit.Limit
+}
+
+// rateLimitKeys picks the buckets of the group a request draws from.
+type rateLimitKeys func(ctx *gin.Context, group string, cfg ratelimit.GroupConfig) []rateLimitCheck
+
+// byIP limits requests per client address. It runs before authentication,
+// so that unauthenticated floods and token guessing are limited too.
+func byIP(ctx *gin.Context, group string, cfg ratelimit.GroupConfig) []rateLimitCheck {
+	return []rateLimitCheck{{key: group + ":ip:" + ctx.ClientIP(), limit: cfg.PerIP}}
+}
+
+// byPrincipal limits requests per authenticated principal. It runs after
+// authentication, so that made-up tokens cannot get fresh buckets.
+func byPrincipal(ctx *gin.Context, group string, cfg ratelimit.GroupConfig) []rateLimitCheck {
+	principal, ok := ctx.Get(handlers.PrincipalKey)
+	if !ok {
+		return nil
+	}
+	p := principal.(entities.Principal)
+	return []rateLimitCheck{{key: fmt.Sprintf("%s:principal:%q:%q", group, p.Tenant, p.Subject), limit: cfg.PerToken}}
+}
+
+// rateLimitMiddleware applies the token buckets keys picks and reports the
+// most restrictive bucket of the request so far in RateLimit-* headers.
+func rateLimitMiddleware(limiter ratelimit.Limiter, group string, cfg ratelimit.GroupConfig, keys rateLimitKeys) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var strictest *ratelimit.Result
+		if earlier, ok := ctx.Get(rateLimitResultKey); ok {
+			result := earlier.(ratelimit.Result)
+			strictest = &result
+		}
+		for _, check := range keys(ctx, group, cfg) {
+			if check.limit.Unlimited() {
+				continue
+			}
+			result, err := limiter.Allow(ctx, check.key, check.limit)
+			if err != nil {
+				// A broken limiter store must not take the API down.
+				logging.FromContext(ctx).Error("Error with rate limiter", "error", err)
+				continue
+			}
+			if strictest == nil || !result.Allowed || (strictest.Allowed && result.Remaining < strictest.Remaining) {
+				strictest = &result
+			}
+			if !result.Allowed {
+				break
+			}
+		}
+		if strictest == nil {
+			ctx.Next()
+			return
+		}
+		ctx.Set(rateLimitResultKey, *strictest)
+
+		ctx.Header("RateLimit-Limit", strconv.Itoa(strictest.Limit))
+		ctx.Header("RateLimit-Remaining", strconv.Itoa(strictest.Remaining))
+		ctx.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(strictest.Reset.Seconds())))
+		if !strictest.Allowed {
+			ctx.Header("Retry-After", strconv.Itoa(ceilSeconds(strictest.RetryAfter.Seconds())))
+			abortWithError(ctx, errRateLimited)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func ceilSeconds(s float64) int {
+	return int(math.Ceil(s))
+}
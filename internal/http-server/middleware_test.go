@@ -0,0 +1,103 @@
+package http_server
+
+import (
+	"avito-tech-backend/internal/core"
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/http-server/handlers"
+	"avito-tech-backend/internal/pkg/access"
+	"avito-tech-backend/internal/pkg/ratelimit"
+	"avito-tech-backend/internal/pkg/web"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRateLimitPerPrincipal(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		// Stands in for authentication.
+		ctx.Set(handlers.PrincipalKey, entities.Principal{Subject: ctx.GetHeader("token"), Tenant: "default"})
+	}, rateLimitMiddleware(ratelimit.NewMemoryLimiter(100), "user", ratelimit.GroupConfig{
+		PerToken: ratelimit.Limit{Rate: 0.5, Burst: 2},
+	}, byPrincipal))
+	router.GET("/", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	get := func(subject string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("token", subject)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("alice")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "2", rec.Header().Get("RateLimit-Reset"))
+
+	require.Equal(t, http.StatusOK, get("alice").Code)
+	rec = get("alice")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "2", rec.Header().Get("Retry-After"))
+	require.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+
+	require.Equal(t, http.StatusOK, get("bob").Code, "each principal has its own bucket")
+}
+
+func TestRateLimitBeforeAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &core.Config{
+		RateLimit: ratelimit.Config{
+			Enabled: true,
+			User:    ratelimit.GroupConfig{PerIP: ratelimit.Limit{Rate: 0.1, Burst: 3}},
+		},
+		Auth: core.AuthConfig{Tokens: []core.TokenConfig{{Token: "user_token", Subject: "user", Role: entities.RoleUser}}},
+	}
+	app := &App{Repository: &core.Repository{Config: cfg}, Limiter: ratelimit.NewMemoryLimiter(100)}
+	app.SetRateLimits(cfg.RateLimit)
+	app.initRoutes()
+
+	codes := make([]int, 0, 5)
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/user_banner?tag_id=1&feature_id=1", nil)
+		req.Header.Set("token", fmt.Sprint("guess-", i))
+		rec := httptest.NewRecorder()
+		app.Router.ServeHTTP(rec, req)
+		codes = append(codes, rec.Code)
+	}
+	require.Equal(t, []int{
+		http.StatusForbidden, http.StatusForbidden, http.StatusForbidden,
+		http.StatusTooManyRequests, http.StatusTooManyRequests,
+	}, codes, "guessed tokens draw from the client address bucket")
+}
+
+func TestDebugRequiresOperator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &core.Config{
+		Server: web.ServerConfig{Profile: true},
+		Auth: core.AuthConfig{Tokens: []core.TokenConfig{
+			{Token: "admin_token", Subject: "admin", Role: entities.RoleAdmin},
+			{Token: "promo_admin_token", Subject: "promo", Role: entities.RoleAdmin, Scope: access.Scope{FeatureIds: []int64{1}}},
+		}},
+	}
+	app := &App{Repository: &core.Repository{Config: cfg}, Limiter: ratelimit.NewMemoryLimiter(100)}
+	app.SetRateLimits(cfg.RateLimit)
+	app.initRoutes()
+	app.Server = web.NewServer(cfg.Server, app.Router)
+	app.initDebugRoutes()
+
+	for _, path := range []string{"/debug/cache", "/debug/db", "/debug/vars", "/debug/pprof/"} {
+		for _, token := range []string{"admin_token", "promo_admin_token"} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("token", token)
+			rec := httptest.NewRecorder()
+			app.Router.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusForbidden, rec.Code, "%s with %s", path, token)
+		}
+	}
+}
@@ -2,78 +2,215 @@ package http_server
 
 import (
 	"avito-tech-backend/internal/core"
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/core/errs"
 	"avito-tech-backend/internal/http-server/handlers"
+	"avito-tech-backend/internal/pkg/access"
+	"avito-tech-backend/internal/pkg/ratelimit"
+	"avito-tech-backend/internal/pkg/tenancy"
 	"avito-tech-backend/internal/pkg/web"
 	"context"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"sync/atomic"
 )
 
 type App struct {
 	Server     web.Server
 	Router     *gin.Engine
 	Repository *core.Repository
+	Limiter    ratelimit.Limiter
+
+	// rateLimits is swapped on config reload.
+	rateLimits atomic.Pointer[ratelimit.Config]
 }
 
 func New(repository *core.Repository) *App {
 	app := &App{
 		Repository: repository,
+		Limiter:    ratelimit.NewMemoryLimiter(repository.Config.RateLimit.MaxBuckets),
 	}
+	app.SetRateLimits(repository.Config.RateLimit)
 	app.initRoutes()
 	app.Server = web.NewServer(repository.Config.Server, app.Router)
+	app.Server.AddReadinessCheck("postgres", repository.Storage.Database.Ping)
+	app.Server.AddReadinessCheck("migrations", repository.Storage.CheckMigrations)
+	app.initDebugRoutes()
 	return app
 }
 
+// initDebugRoutes mounts diagnostics when profiling is on. They span all
+// tenants and expose process internals, so only operators may use them.
+func (app *App) initDebugRoutes() {
+	if debug := app.Server.Debug(app.authOperatorMiddleware()); debug != nil {
+		debug.GET("/cache", app.mappedHandler(handlers.GetCacheDebug))
+		debug.GET("/db", app.mappedHandler(handlers.GetDatabaseDebug))
+	}
+}
+
 func (app *App) Start(ctx context.Context) error {
 	return app.Server.Run(ctx)
 }
 
 func (app *App) initRoutes() {
-	app.Router = gin.Default()
+	app.Router = gin.New()
+	// Lets handlers pass *gin.Context down as context.Context and still reach
+	// the request-scoped logger.
+	app.Router.ContextWithFallback = true
+	app.Router.Use(requestContextMiddleware(), gin.CustomRecovery(func(ctx *gin.Context, recovered any) {
+		abortWithError(ctx, fmt.Errorf("panic: %v", recovered))
+	}))
 
-	app.Router.GET("/user_banner", authMiddleware(), app.mappedHandler(handlers.GetUserBanner))
+	user := app.Router.Group("/")
+	user.Use(app.rateLimit("user", userLimits, byIP), app.authMiddleware(), app.rateLimit("user", userLimits, byPrincipal))
+	{
+		user.GET("/user_banner", app.mappedHandler(handlers.GetUserBanner))
+		user.GET("/user_banner/list", app.mappedHandler(handlers.GetUserBanners))
+	}
 
 	admin := app.Router.Group("/")
-	admin.Use(authAdminMiddleware())
+	admin.Use(app.rateLimit("admin", adminLimits, byIP), app.authAdminMiddleware(), app.rateLimit("admin", adminLimits, byPrincipal))
+	{
+		admin.GET("/banner", app.mappedHandler(handlers.GetBanners))
+		admin.POST("/banner", app.mappedHandler(handlers.CreateBanner))
+		admin.GET("/banner/trash", app.mappedHandler(handlers.GetDeletedBanners))
+		admin.GET("/banner/:id", app.mappedHandler(handlers.GetBanner))
+		admin.POST("/banner/:id/restore", app.mappedHandler(handlers.RestoreBanner))
+		admin.GET("/banner/:id/drafts", app.mappedHandler(handlers.GetDrafts))
+		admin.POST("/banner/:id/drafts", app.mappedHandler(handlers.CreateDraft))
+		admin.POST("/draft/:id/submit", app.mappedHandler(handlers.SubmitDraft))
+		admin.POST("/draft/:id/approve", app.mappedHandler(handlers.ApproveDraft))
+		admin.POST("/draft/:id/reject", app.mappedHandler(handlers.RejectDraft))
+		admin.PATCH("/banner/:id", app.mappedHandler(handlers.UpdateBanner))
+		admin.DELETE("/banner/:id", app.mappedHandler(handlers.DeleteBanner))
+
+		admin.GET("/feature", app.mappedHandler(handlers.GetFeatures))
+		admin.POST("/feature", app.mappedHandler(handlers.CreateFeature))
+		admin.GET("/feature/:id", app.mappedHandler(handlers.GetFeature))
+		admin.PATCH("/feature/:id", app.mappedHandler(handlers.UpdateFeature))
+		admin.DELETE("/feature/:id", app.mappedHandler(handlers.DeleteFeature))
+
+		admin.GET("/tag", app.mappedHandler(handlers.GetTags))
+		admin.POST("/tag", app.mappedHandler(handlers.CreateTag))
+		admin.GET("/tag/:id", app.mappedHandler(handlers.GetTag))
+		admin.PATCH("/tag/:id", app.mappedHandler(handlers.UpdateTag))
+		admin.DELETE("/tag/:id", app.mappedHandler(handlers.DeleteTag))
+	}
+
+	operator := app.Router.Group("/")
+	operator.Use(app.rateLimit("admin", adminLimits, byIP), app.authOperatorMiddleware(), app.rateLimit("admin", adminLimits, byPrincipal))
 	{
-		app.Router.GET("/banner", app.mappedHandler(handlers.GetBanners))
-		app.Router.POST("/banner", app.mappedHandler(handlers.CreateBanner))
-		app.Router.PATCH("/banner/:id", app.mappedHandler(handlers.UpdateBanner))
-		app.Router.DELETE("/banner/:id", app.mappedHandler(handlers.DeleteBanner))
+		operator.GET("/tenant", app.mappedHandler(handlers.GetTenants))
+		operator.POST("/tenant", app.mappedHandler(handlers.CreateTenant))
+		operator.GET("/tenant/:name", app.mappedHandler(handlers.GetTenant))
+		operator.PATCH("/tenant/:name", app.mappedHandler(handlers.UpdateTenant))
+		operator.DELETE("/tenant/:name", app.mappedHandler(handlers.DeleteTenant))
 	}
 }
 
 func (app *App) mappedHandler(handler func(*gin.Context, *core.Repository) error) gin.HandlerFunc {
 
 	return func(ctx *gin.Context) {
-
 		if err := handler(ctx, app.Repository); err != nil {
-			_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+			abortWithError(ctx, err)
 		}
 	}
 }
 
-func authAdminMiddleware() gin.HandlerFunc {
+// SetRateLimits replaces the limits applied to requests from now on.
+func (app *App) SetRateLimits(cfg ratelimit.Config) {
+	app.rateLimits.Store(&cfg)
+}
+
+// rateLimit applies the group limits picked from the current config to the
+// buckets keys picks.
+func (app *App) rateLimit(group string, limits func(ratelimit.Config) ratelimit.GroupConfig, keys rateLimitKeys) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		token := ctx.GetHeader("token")
-		if token == "" {
-			ctx.AbortWithStatus(http.StatusUnauthorized)
-		}
-		if token != "admin_token" {
-			ctx.AbortWithStatus(http.StatusForbidden)
+		cfg := app.rateLimits.Load()
+		if !cfg.Enabled {
+			ctx.Next()
+			return
 		}
-		ctx.Next()
+		rateLimitMiddleware(app.Limiter, group, limits(*cfg), keys)(ctx)
 	}
 }
-func authMiddleware() gin.HandlerFunc {
+
+func userLimits(cfg ratelimit.Config) ratelimit.GroupConfig {
+	return cfg.User
+}
+
+func adminLimits(cfg ratelimit.Config) ratelimit.GroupConfig {
+	return cfg.Admin
+}
+
+var (
+	errMissingToken   = errs.Unauthorized("missing_token", "token header is required")
+	errForbiddenToken = errs.Forbidden("forbidden_token", "token does not grant access to this resource")
+	errUnknownTenant  = errs.Forbidden("unknown_tenant", "token belongs to a tenant that does not exist")
+)
+
+func (app *App) authAdminMiddleware() gin.HandlerFunc {
+	return app.authenticate(func(principal entities.Principal) bool {
+		return principal.IsAdmin()
+	})
+}
+
+func (app *App) authOperatorMiddleware() gin.HandlerFunc {
+	return app.authenticate(func(principal entities.Principal) bool {
+		return principal.IsOperator()
+	})
+}
+
+func (app *App) authMiddleware() gin.HandlerFunc {
+	return app.authenticate(func(principal entities.Principal) bool {
+		return principal.Role == entities.RoleUser || principal.IsAdmin()
+	})
+}
+
+// authenticate resolves the token header, or else a verified client
+// certificate, to a principal and lets the request through if allowed
+// accepts it and its tenant exists.
+func (app *App) authenticate(allowed func(entities.Principal) bool) gin.HandlerFunc {
+	auth := app.Repository.Config.Auth
 	return func(ctx *gin.Context) {
 		token := ctx.GetHeader("token")
-		if token == "" {
-			ctx.AbortWithStatus(http.StatusUnauthorized)
+		var principal entities.Principal
+		var ok bool
+		switch subject, verified := clientCertSubject(ctx.Request); {
+		case token != "":
+			principal, ok = auth.Lookup(token)
+		case verified:
+			principal, ok = auth.LookupCert(subject)
+		default:
+			abortWithError(ctx, errMissingToken)
+			return
+		}
+		if !ok || !allowed(principal) {
+			abortWithError(ctx, errForbiddenToken)
+			return
 		}
-		if token != "user_token" && token != "admin_token" {
-			ctx.AbortWithStatus(http.StatusForbidden)
+		exists, err := app.Repository.Actions.TenantExists(ctx, principal.Tenant)
+		if err != nil {
+			abortWithError(ctx, err)
+			return
 		}
+		if !exists {
+			abortWithError(ctx, errUnknownTenant.WithDetail("tenant", principal.Tenant))
+			return
+		}
+		ctx.Set(handlers.PrincipalKey, principal)
+		requestCtx := tenancy.WithTenant(ctx.Request.Context(), principal.Tenant)
+		ctx.Request = ctx.Request.WithContext(access.WithScope(requestCtx, principal.Scope))
 		ctx.Next()
 	}
 }
+
+// clientCertSubject returns the common name of the client certificate the
+// TLS handshake verified, if any.
+func clientCertSubject(req *http.Request) (string, bool) {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	return req.TLS.VerifiedChains[0][0].Subject.CommonName, true
+}
@@ -0,0 +1,88 @@
+// Package access carries the part of the catalog an admin token may manage
+// through contexts. Unlike tenancy it fails open: a context without a scope
+// is unrestricted, which is what background jobs need.
+package access
+
+import (
+	"context"
+	"sort"
+)
+
+type scopeCtxKey struct{}
+
+// Range is an inclusive range of ids.
+type Range struct {
+	From int64 `yaml:"from" json:"from"`
+	To   int64 `yaml:"to" json:"to"`
+}
+
+func (r Range) Contains(id int64) bool {
+	return r.From <= id && id <= r.To
+}
+
+// Scope limits an admin to banners of the listed features whose tags all lie
+// in the listed ranges. An empty list leaves that dimension unrestricted, so
+// the zero Scope allows everything.
+type Scope struct {
+	FeatureIds []int64 `yaml:"featureIds" json:"feature_ids,omitempty"`
+	TagRanges  []Range `yaml:"tagRanges" json:"tag_ranges,omitempty"`
+}
+
+func (s Scope) Unrestricted() bool {
+	return len(s.FeatureIds) == 0 && len(s.TagRanges) == 0
+}
+
+func (s Scope) AllowsFeature(id int64) bool {
+	if len(s.FeatureIds) == 0 {
+		return true
+	}
+	for _, allowed := range s.FeatureIds {
+		if allowed == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Scope) AllowsTag(id int64) bool {
+	if len(s.TagRanges) == 0 {
+		return true
+	}
+	for _, r := range s.TagRanges {
+		if r.Contains(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Violations returns the feature and tag ids outside the scope, sorted and
+// without duplicates. Both are empty when the scope allows them all.
+func (s Scope) Violations(featureIds []int64, tagIds []int64) ([]int64, []int64) {
+	return reject(featureIds, s.AllowsFeature), reject(tagIds, s.AllowsTag)
+}
+
+func reject(ids []int64, allowed func(int64) bool) []int64 {
+	seen := make(map[int64]struct{})
+	rejected := make([]int64, 0)
+	for _, id := range ids {
+		if _, ok := seen[id]; ok || allowed(id) {
+			continue
+		}
+		seen[id] = struct{}{}
+		rejected = append(rejected, id)
+	}
+	sort.Slice(rejected, func(i, j int) bool { return rejected[i] < rejected[j] })
+	return rejected
+}
+
+// WithScope returns a copy of ctx limited to scope.
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeCtxKey{}, scope)
+}
+
+// FromContext returns the scope of ctx. Contexts without one are unrestricted.
+func FromContext(ctx context.Context) Scope {
+	scope, _ := ctx.Value(scopeCtxKey{}).(Scope)
+	return scope
+}
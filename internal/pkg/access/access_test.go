@@ -0,0 +1,25 @@
+package access
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestScopeViolations(t *testing.T) {
+	scope := Scope{
+		FeatureIds: []int64{1, 2},
+		TagRanges:  []Range{{From: 10, To: 19}, {From: 30, To: 30}},
+	}
+
+	features, tags := scope.Violations([]int64{2}, []int64{10, 19, 30})
+	require.Empty(t, features)
+	require.Empty(t, tags)
+
+	features, tags = scope.Violations([]int64{3}, []int64{31, 9, 31, 15})
+	require.Equal(t, []int64{3}, features)
+	require.Equal(t, []int64{9, 31}, tags)
+
+	features, tags = Scope{}.Violations([]int64{3}, []int64{31})
+	require.Empty(t, features)
+	require.Empty(t, tags)
+}
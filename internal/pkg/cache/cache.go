@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config represents configuration for Cache.
+type Config struct {
+	TTL time.Duration `yaml:"ttl"`
+	// RefreshAhead reloads entries that were read while cached this long
+	// before they expire; zero disables refreshing.
+	RefreshAhead time.Duration `yaml:"refreshAhead"`
+	Warmup       WarmupConfig  `yaml:"warmup"`
+}
+
+// WarmupConfig controls filling the cache on startup.
+type WarmupConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// TopN limits warm-up to the keys most requested over the last week;
+	// zero loads all of them.
+	TopN int `yaml:"topN"`
+	// BlockReadiness keeps the instance unready until warm-up succeeds.
+	BlockReadiness bool `yaml:"blockReadiness"`
+}
+
+type item[V any] struct {
+	value     V
+	expiresAt time.Time
+	// read is set once the entry is returned by Get; only such entries are
+	// worth refreshing.
+	read atomic.Bool
+}
+
+// Cache is an in-memory key-value store whose entries expire after TTL.
+type Cache[K comparable, V any] struct {
+	mu    sync.RWMutex
+	ttl   atomic.Int64
+	items map[K]*item[V]
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// New returns new *Cache.
+func New[K comparable, V any](ttl time.Duration) *Cache[K, V] {
+	c := &Cache[K, V]{
+		items: make(map[K]*item[V]),
+		now:   time.Now,
+	}
+	c.ttl.Store(int64(ttl))
+	return c
+}
+
+func (c *Cache[K, V]) TTL() time.Duration {
+	return time.Duration(c.ttl.Load())
+}
+
+// SetTTL changes the lifetime of entries set from now on.
+func (c *Cache[K, V]) SetTTL(ttl time.Duration) {
+	c.ttl.Store(int64(ttl))
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	it, ok := c.items[key]
+	c.mu.RUnlock()
+	if !ok || !c.now().Before(it.expiresAt) {
+		c.misses.Add(1)
+		var zero V
+		return zero, false
+	}
+	c.hits.Add(1)
+	it.read.Store(true)
+	return it.value, true
+}
+
+func (c *Cache[K, V]) Set(key K, value V) {
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ttl := c.TTL()
+	c.items[key] = &item[V]{value: value, expiresAt: now.Add(ttl)}
+	if now.Sub(c.lastSweep) >= ttl {
+		c.sweep(now)
+	}
+}
+
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+}
+
+// Expiring returns keys of entries that were read and expire within the
+// given duration, the candidates for refreshing ahead of expiry.
+func (c *Cache[K, V]) Expiring(within time.Duration) []K {
+	deadline := c.now().Add(within)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys := make([]K, 0)
+	for key, it := range c.items {
+		if it.read.Load() && it.expiresAt.Before(deadline) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// sweep drops expired entries; the caller must hold the write lock.
+func (c *Cache[K, V]) sweep(now time.Time) {
+	c.lastSweep = now
+	for key, it := range c.items {
+		if !now.Before(it.expiresAt) {
+			delete(c.items, key)
+		}
+	}
+}
+
+// Stats is a snapshot of cache counters.
+type Stats struct {
+	TTL     time.Duration
+	Entries int
+	Hits    uint64
+	Misses  uint64
+}
+
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.RLock()
+	entries := len(c.items)
+	c.mu.RUnlock()
+	return Stats{
+		TTL:     c.TTL(),
+		Entries: entries,
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+	}
+}
+
+// Keys returns up to limit keys of entries that have not expired, in no
+// particular order, and whether some were left out. A non-positive limit
+// returns all of them.
+func (c *Cache[K, V]) Keys(limit int) ([]K, bool) {
+	now := c.now()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys := make([]K, 0, len(c.items))
+	for key, it := range c.items {
+		if !now.Before(it.expiresAt) {
+			continue
+		}
+		if limit > 0 && len(keys) == limit {
+			return keys, true
+		}
+		keys = append(keys, key)
+	}
+	return keys, false
+}
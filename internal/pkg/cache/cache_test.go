@@ -0,0 +1,26 @@
+package cache
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestExpiring(t *testing.T) {
+	now := time.Now()
+	c := New[string, int](time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set("hot", 1)
+	c.Set("cold", 2)
+	_, ok := c.Get("hot")
+	require.True(t, ok)
+
+	require.Empty(t, c.Expiring(10*time.Second), "entries are far from expiry")
+
+	now = now.Add(55 * time.Second)
+	require.Equal(t, []string{"hot"}, c.Expiring(10*time.Second), "only read entries are refreshed")
+
+	c.Set("hot", 3)
+	require.Empty(t, c.Expiring(10*time.Second), "a refreshed entry must be read again")
+}
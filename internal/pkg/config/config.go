@@ -1,7 +1,9 @@
 package config
 
 import (
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"reflect"
 	"strings"
 )
 
@@ -13,6 +15,39 @@ func WithConfigPath(p string) LoaderOption {
 	}
 }
 
+// WithEnv binds every key of target to an environment variable named after
+// it, e.g. STORAGE_POOL_MAXCONNS for storage.pool.maxConns. AutomaticEnv
+// alone only covers keys present in the file or defaults.
+func WithEnv(target any) LoaderOption {
+	return func(v *viper.Viper) {
+		for _, key := range Keys(target) {
+			_ = v.BindEnv(key)
+		}
+	}
+}
+
+// WithFlags binds the flags DefineFlags added to fs, which must be parsed
+// already. Flags set on the command line take precedence over the
+// environment and the file; unset ones are not bound, as viper would fall
+// back to their empty defaults instead of the loader's.
+func WithFlags(fs *pflag.FlagSet, target any) LoaderOption {
+	return func(v *viper.Viper) {
+		for _, key := range Keys(target) {
+			if flag := fs.Lookup(key); flag != nil && flag.Changed {
+				_ = v.BindPFlag(key, flag)
+			}
+		}
+	}
+}
+
+// DefineFlags adds a string flag named after every key of target to fs.
+// Values are decoded like the file ones, so "--cache.ttl=1m" works.
+func DefineFlags(fs *pflag.FlagSet, target any) {
+	for _, key := range Keys(target) {
+		fs.String(key, "", "overrides "+key)
+	}
+}
+
 func PrepareLoader(options ...LoaderOption) *viper.Viper {
 	v := viper.New()
 	v.AutomaticEnv()
@@ -22,3 +57,42 @@ func PrepareLoader(options ...LoaderOption) *viper.Viper {
 	}
 	return v
 }
+
+// Keys returns the dotted keys of the scalar settings of target, a struct or
+// a pointer to one, named after their yaml tags. Lists of structs can only
+// be set in the file and are left out.
+func Keys(target any) []string {
+	return appendKeys(nil, "", reflect.TypeOf(target))
+}
+
+func appendKeys(keys []string, prefix string, t reflect.Type) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		key := prefix + name
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			keys = appendKeys(keys, key+".", field.Type)
+		case reflect.Slice, reflect.Map:
+			if elem := field.Type.Elem().Kind(); elem == reflect.Struct || elem == reflect.Map || elem == reflect.Slice {
+				continue
+			}
+			keys = append(keys, key)
+		default:
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,186 @@
+// Package health runs readiness checks and tracks background workers.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusFail Status = "fail"
+)
+
+// Reason tells why a check failed without revealing its error, which may
+// name hosts, users or queries.
+type Reason string
+
+const (
+	ReasonUnavailable Reason = "unavailable"
+	ReasonStale       Reason = "stale"
+	ReasonTimeout     Reason = "timeout"
+)
+
+// ErrStale is wrapped by errors of workers that stopped completing runs.
+var ErrStale = errors.New("no successful run")
+
+// Check reports why a dependency cannot serve requests, or nil.
+type Check func(ctx context.Context) error
+
+// Result is the outcome of a single check. Err is the error behind Reason;
+// it is meant for logs and is not serialized.
+type Result struct {
+	Status    Status  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Reason    Reason  `json:"reason,omitempty"`
+	Err       error   `json:"-"`
+}
+
+// Report is the outcome of all checks; it is ok only if every check is.
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Checker runs named checks concurrently, each bounded by a timeout.
+type Checker struct {
+	mu      sync.RWMutex
+	checks  map[string]Check
+	timeout time.Duration
+}
+
+// NewChecker returns new *Checker. A non-positive timeout leaves checks
+// bounded by the caller's context only.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{
+		checks:  make(map[string]Check),
+		timeout: timeout,
+	}
+}
+
+// Register adds check under name, replacing a check registered before.
+func (c *Checker) Register(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.RUnlock()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := c.run(ctx, check)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusFail
+			}
+		}(name, check)
+	}
+	wg.Wait()
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) Result {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	err := check(ctx)
+	result := Result{
+		Status:    StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Reason = reasonOf(err)
+		result.Err = err
+	}
+	return result
+}
+
+func reasonOf(err error) Reason {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ReasonTimeout
+	case errors.Is(err, ErrStale):
+		return ReasonStale
+	default:
+		return ReasonUnavailable
+	}
+}
+
+// Heartbeat tracks a background worker: the worker beats after each
+// successful run and is considered stuck once maxAge passes without one.
+type Heartbeat struct {
+	mu      sync.Mutex
+	maxAge  time.Duration
+	last    time.Time
+	lastErr error
+	now     func() time.Time
+}
+
+// NewHeartbeat returns new *Heartbeat. The worker gets maxAge from now to
+// complete its first run.
+func NewHeartbeat(maxAge time.Duration) *Heartbeat {
+	h := &Heartbeat{maxAge: maxAge, now: time.Now}
+	h.last = h.now()
+	return h
+}
+
+// SetMaxAge changes how long the worker may go without a successful run,
+// for when its schedule changes.
+func (h *Heartbeat) SetMaxAge(maxAge time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.maxAge = maxAge
+}
+
+// Beat records a successful run.
+func (h *Heartbeat) Beat() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last = h.now()
+	h.lastErr = nil
+}
+
+// Fail records a failed run. It does not make the worker unhealthy by
+// itself, so that a single blip does not take the instance out of service.
+func (h *Heartbeat) Fail(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastErr = err
+}
+
+// Check fails if the worker has not completed a run within maxAge.
+func (h *Heartbeat) Check(context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	age := h.now().Sub(h.last)
+	if age <= h.maxAge {
+		return nil
+	}
+	if h.lastErr != nil {
+		return fmt.Errorf("%w for %s: %w", ErrStale, age.Round(time.Second), h.lastErr)
+	}
+	return fmt.Errorf("%w for %s", ErrStale, age.Round(time.Second))
+}
@@ -0,0 +1,56 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCheckerRun(t *testing.T) {
+	checker := NewChecker(20 * time.Millisecond)
+	checker.Register("db", func(context.Context) error { return nil })
+	require.Equal(t, StatusOK, checker.Run(context.Background()).Status)
+
+	checker.Register("broken", func(context.Context) error { return errors.New("boom") })
+	checker.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	report := checker.Run(context.Background())
+	require.Equal(t, StatusFail, report.Status)
+	require.Equal(t, StatusOK, report.Checks["db"].Status)
+	require.Equal(t, ReasonUnavailable, report.Checks["broken"].Reason)
+	require.EqualError(t, report.Checks["broken"].Err, "boom")
+	require.Equal(t, ReasonTimeout, report.Checks["slow"].Reason)
+	require.ErrorIs(t, report.Checks["slow"].Err, context.DeadlineExceeded)
+	require.GreaterOrEqual(t, report.Checks["slow"].LatencyMs, float64(20))
+
+	h := NewHeartbeat(0)
+	h.now = func() time.Time { return time.Now().Add(time.Second) }
+	checker.Register("worker", h.Check)
+	require.Equal(t, ReasonStale, checker.Run(context.Background()).Checks["worker"].Reason)
+
+	body, err := json.Marshal(report)
+	require.NoError(t, err)
+	require.NotContains(t, string(body), "boom", "errors are for logs only")
+}
+
+func TestHeartbeat(t *testing.T) {
+	now := time.Now()
+	h := NewHeartbeat(time.Minute)
+	h.now = func() time.Time { return now }
+	h.Beat()
+
+	now = now.Add(time.Minute)
+	h.Fail(errors.New("db is down"))
+	require.NoError(t, h.Check(context.Background()), "a failed run alone does not make the worker unhealthy")
+
+	now = now.Add(time.Second)
+	require.ErrorContains(t, h.Check(context.Background()), "no successful run for 1m1s: db is down")
+
+	h.Beat()
+	require.NoError(t, h.Check(context.Background()))
+}
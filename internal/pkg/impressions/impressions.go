@@ -0,0 +1,47 @@
+package impressions
+
+import (
+	"context"
+	"time"
+)
+
+// Config represents configuration for the impression store.
+type Config struct {
+	// Shards splits the in-memory counters to reduce lock contention.
+	Shards int `yaml:"shards"`
+	// MaxEntries bounds the number of counters kept in memory; the least
+	// recently used ones are dropped first.
+	MaxEntries int `yaml:"maxEntries"`
+	// Persist keeps counters in Postgres so that caps hold across restarts
+	// and instances.
+	Persist bool `yaml:"persist"`
+}
+
+// Key identifies the impressions of one banner shown to one user on one day.
+type Key struct {
+	UserId   string
+	BannerId int64
+	Day      time.Time
+}
+
+// NewKey returns the Key for an impression happening at t.
+func NewKey(userId string, bannerId int64, t time.Time) Key {
+	return Key{UserId: userId, BannerId: bannerId, Day: Day(t)}
+}
+
+// Day truncates t to the start of its UTC day.
+func Day(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Store counts banner impressions. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Take records an impression for key unless limit impressions were
+	// already recorded, and reports whether it did.
+	Take(ctx context.Context, key Key, limit int64) (bool, error)
+	// Allows reports whether Take would record an impression for key,
+	// without recording one.
+	Allows(ctx context.Context, key Key, limit int64) (bool, error)
+}
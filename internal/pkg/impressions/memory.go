@@ -0,0 +1,128 @@
+package impressions
+
+import (
+	"container/list"
+	"context"
+	"hash/fnv"
+	"strconv"
+	"sync"
+)
+
+var _ Store = (*MemoryStore)(nil)
+
+type counter struct {
+	key   Key
+	count int64
+}
+
+type shard struct {
+	mu       sync.Mutex
+	counters map[Key]*list.Element
+	// recent orders counters from the most to the least recently used.
+	recent *list.List
+	max    int
+}
+
+// MemoryStore is a process-local Store split into independently locked
+// shards, each holding a bounded number of counters.
+//
+// With a backing Store the backing one is authoritative and MemoryStore only
+// remembers which users already reached their cap, sparing the backing store
+// a round trip for them.
+type MemoryStore struct {
+	shards  []*shard
+	backing Store
+}
+
+// NewMemoryStore returns new *MemoryStore. backing may be nil.
+func NewMemoryStore(cfg Config, backing Store) *MemoryStore {
+	shards := cfg.Shards
+	if shards < 1 {
+		shards = 1
+	}
+	perShard := cfg.MaxEntries / shards
+	if perShard < 1 {
+		perShard = 1
+	}
+	s := &MemoryStore{
+		shards:  make([]*shard, shards),
+		backing: backing,
+	}
+	for i := range s.shards {
+		s.shards[i] = &shard{
+			counters: make(map[Key]*list.Element),
+			recent:   list.New(),
+			max:      perShard,
+		}
+	}
+	return s
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key Key, limit int64) (bool, error) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	if sh.get(key) >= limit {
+		sh.mu.Unlock()
+		return false, nil
+	}
+	if s.backing == nil {
+		sh.add(key, 1)
+		sh.mu.Unlock()
+		return true, nil
+	}
+	sh.mu.Unlock()
+
+	taken, err := s.backing.Take(ctx, key, limit)
+	if err != nil {
+		return false, err
+	}
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if taken {
+		sh.add(key, 1)
+	} else {
+		sh.add(key, limit-sh.get(key))
+	}
+	return taken, nil
+}
+
+func (s *MemoryStore) Allows(ctx context.Context, key Key, limit int64) (bool, error) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	reached := sh.get(key) >= limit
+	sh.mu.Unlock()
+	if reached || s.backing == nil {
+		return !reached, nil
+	}
+	return s.backing.Allows(ctx, key, limit)
+}
+
+func (s *MemoryStore) shard(key Key) *shard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key.UserId))
+	_, _ = h.Write([]byte(strconv.FormatInt(key.BannerId, 10)))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+func (sh *shard) get(key Key) int64 {
+	el, ok := sh.counters[key]
+	if !ok {
+		return 0
+	}
+	sh.recent.MoveToFront(el)
+	return el.Value.(*counter).count
+}
+
+func (sh *shard) add(key Key, delta int64) {
+	if el, ok := sh.counters[key]; ok {
+		el.Value.(*counter).count += delta
+		sh.recent.MoveToFront(el)
+		return
+	}
+	sh.counters[key] = sh.recent.PushFront(&counter{key: key, count: delta})
+	for sh.recent.Len() > sh.max {
+		oldest := sh.recent.Back()
+		sh.recent.Remove(oldest)
+		delete(sh.counters, oldest.Value.(*counter).key)
+	}
+}
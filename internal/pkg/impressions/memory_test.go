@@ -0,0 +1,82 @@
+package impressions
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type countingStore struct {
+	calls int
+	count map[Key]int64
+}
+
+func (s *countingStore) Take(_ context.Context, key Key, limit int64) (bool, error) {
+	s.calls++
+	if s.count[key] >= limit {
+		return false, nil
+	}
+	s.count[key]++
+	return true, nil
+}
+
+func (s *countingStore) Allows(_ context.Context, key Key, limit int64) (bool, error) {
+	s.calls++
+	return s.count[key] < limit, nil
+}
+
+func TestMemoryStoreCap(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(Config{Shards: 4, MaxEntries: 100}, nil)
+	now := time.Date(2024, 4, 10, 23, 0, 0, 0, time.UTC)
+	key := NewKey("u1", 1, now)
+	for i := 0; i < 3; i++ {
+		taken, err := store.Take(ctx, key, 3)
+		require.NoError(t, err)
+		require.True(t, taken)
+	}
+	allowed, err := store.Allows(ctx, key, 3)
+	require.NoError(t, err)
+	require.False(t, allowed)
+	taken, err := store.Take(ctx, key, 3)
+	require.NoError(t, err)
+	require.False(t, taken)
+
+	taken, err = store.Take(ctx, NewKey("u2", 1, now), 3)
+	require.NoError(t, err)
+	require.True(t, taken)
+	taken, err = store.Take(ctx, NewKey("u1", 1, now.Add(2*time.Hour)), 3)
+	require.NoError(t, err)
+	require.True(t, taken, "counters reset on the next UTC day")
+}
+
+func TestMemoryStoreBounded(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(Config{Shards: 1, MaxEntries: 2}, nil)
+	now := time.Now()
+	for _, user := range []string{"u1", "u2", "u3"} {
+		_, err := store.Take(ctx, NewKey(user, 1, now), 1)
+		require.NoError(t, err)
+	}
+	require.Len(t, store.shards[0].counters, 2)
+	taken, err := store.Take(ctx, NewKey("u1", 1, now), 1)
+	require.NoError(t, err)
+	require.True(t, taken, "evicted counters start over")
+}
+
+func TestMemoryStoreBacking(t *testing.T) {
+	ctx := context.Background()
+	backing := &countingStore{count: make(map[Key]int64)}
+	store := NewMemoryStore(Config{Shards: 2, MaxEntries: 10}, backing)
+	key := NewKey("u1", 1, time.Now())
+	backing.count[key] = 2
+
+	taken, err := store.Take(ctx, key, 2)
+	require.NoError(t, err)
+	require.False(t, taken)
+	taken, err = store.Take(ctx, key, 2)
+	require.NoError(t, err)
+	require.False(t, taken)
+	require.Equal(t, 1, backing.calls, "capped users are answered from memory")
+}
@@ -5,20 +5,32 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
 )
 
 type txCtxKey struct{}
 
-func NewDatabase(pool *pgxpool.Pool) *Database {
+type primaryCtxKey struct{}
+
+// NewDatabase returns a database writing to primary. Reads outside
+// transactions are spread over healthy replicas, if any are given.
+func NewDatabase(primary *pgxpool.Pool, replicas ...*pgxpool.Pool) *Database {
 	return &Database{
-		pool: pool,
+		pool:     primary,
+		replicas: newReplicaSet(replicas),
 	}
 }
 
 type Database struct {
-	pool *pgxpool.Pool
+	pool     *pgxpool.Pool
+	replicas *replicaSet
+	// QueryTimeout bounds every QuerySq call; zero leaves it to the caller's context.
+	QueryTimeout time.Duration
 }
 
+// QuerySq runs query in the transaction carried by ctx, if any. Otherwise
+// SELECTs go to a replica unless ctx asks for the primary, and everything
+// else goes to the primary.
 func (d *Database) QuerySq(ctx context.Context, query sq.Sqlizer) (pgx.Rows, error) {
 	tx, withTransaction := TransactionFromContext(ctx)
 
@@ -26,10 +38,58 @@ func (d *Database) QuerySq(ctx context.Context, query sq.Sqlizer) (pgx.Rows, err
 	if err != nil {
 		return nil, err
 	}
+	if d.QueryTimeout <= 0 {
+		if withTransaction {
+			return tx.Query(ctx, querySql, args...)
+		}
+		return d.poolFor(ctx, query).Query(ctx, querySql, args...)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, d.QueryTimeout)
+	var rows pgx.Rows
 	if withTransaction {
-		return tx.Query(ctx, querySql, args...)
+		rows, err = tx.Query(queryCtx, querySql, args...)
+	} else {
+		rows, err = d.poolFor(ctx, query).Query(queryCtx, querySql, args...)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &deadlineRows{Rows: rows, cancel: cancel}, nil
+}
+
+func (d *Database) poolFor(ctx context.Context, query sq.Sqlizer) *pgxpool.Pool {
+	if _, isRead := query.(sq.SelectBuilder); !isRead || PrimaryRequested(ctx) {
+		return d.pool
+	}
+	if replica := d.replicas.next(); replica != nil {
+		return replica
+	}
+	return d.pool
+}
+
+// Ping checks that the primary answers.
+func (d *Database) Ping(ctx context.Context) error {
+	return d.pool.Ping(ctx)
+}
+
+// WithTransaction runs fn in a transaction carried by the context passed to
+// it; QuerySq calls made with that context join the transaction. Nested calls
+// reuse the outer transaction. Transactions always run on the primary.
+func (d *Database) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := TransactionFromContext(ctx); ok {
+		return fn(ctx)
+	}
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return err
 	}
-	return d.pool.Query(ctx, querySql, args...)
+	if err := fn(context.WithValue(ctx, txCtxKey{}, tx)); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 func TransactionFromContext(ctx context.Context) (pgx.Tx, bool) {
@@ -38,3 +98,14 @@ func TransactionFromContext(ctx context.Context) (pgx.Tx, bool) {
 	}
 	return nil, false
 }
+
+// WithPrimary returns a copy of ctx whose reads go to the primary, for
+// callers that must see their own or the latest writes.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryCtxKey{}, true)
+}
+
+func PrimaryRequested(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryCtxKey{}).(bool)
+	return primary
+}
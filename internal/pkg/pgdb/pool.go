@@ -0,0 +1,62 @@
+package pgdb
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"strconv"
+	"time"
+)
+
+// PoolConfig tunes connection pools. Zero values keep the pgx defaults.
+type PoolConfig struct {
+	MaxConns          int32         `yaml:"maxConns"`
+	MinConns          int32         `yaml:"minConns"`
+	MaxConnLifetime   time.Duration `yaml:"maxConnLifetime"`
+	MaxConnIdleTime   time.Duration `yaml:"maxConnIdleTime"`
+	HealthCheckPeriod time.Duration `yaml:"healthCheckPeriod"`
+	// StatementTimeout makes the server abort statements running longer.
+	StatementTimeout time.Duration `yaml:"statementTimeout"`
+	// QueryTimeout bounds each QuerySq call on the client side, including
+	// waiting for a free connection and reading the rows.
+	QueryTimeout    time.Duration `yaml:"queryTimeout"`
+	ApplicationName string        `yaml:"applicationName"`
+}
+
+// Connect opens a pool to url configured by cfg.
+func Connect(ctx context.Context, url string, cfg PoolConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := parsePoolConfig(url, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return pgxpool.ConnectConfig(ctx, poolConfig)
+}
+
+func parsePoolConfig(url string, cfg PoolConfig) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MaxConns > 0 {
+		poolConfig.MaxConns = cfg.MaxConns
+	}
+	if cfg.MinConns > 0 {
+		poolConfig.MinConns = cfg.MinConns
+	}
+	if cfg.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+	params := poolConfig.ConnConfig.RuntimeParams
+	if cfg.StatementTimeout > 0 {
+		params["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+	if cfg.ApplicationName != "" {
+		params["application_name"] = cfg.ApplicationName
+	}
+	return poolConfig, nil
+}
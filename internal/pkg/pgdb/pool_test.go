@@ -0,0 +1,24 @@
+package pgdb
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParsePoolConfig(t *testing.T) {
+	cfg, err := parsePoolConfig("postgres://u:p@localhost:5432/db?pool_max_conns=7", PoolConfig{
+		MinConns:          2,
+		MaxConnLifetime:   time.Hour,
+		HealthCheckPeriod: 30 * time.Second,
+		StatementTimeout:  1500 * time.Millisecond,
+		ApplicationName:   "banners",
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 7, cfg.MaxConns, "settings left zero keep the URL and pgx defaults")
+	require.EqualValues(t, 2, cfg.MinConns)
+	require.Equal(t, time.Hour, cfg.MaxConnLifetime)
+	require.Equal(t, 30*time.Second, cfg.HealthCheckPeriod)
+	require.Equal(t, "1500", cfg.ConnConfig.RuntimeParams["statement_timeout"])
+	require.Equal(t, "banners", cfg.ConnConfig.RuntimeParams["application_name"])
+}
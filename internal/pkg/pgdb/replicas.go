@@ -0,0 +1,82 @@
+package pgdb
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// replicaSet picks read replicas round-robin, skipping the ones that failed
+// their last health check.
+type replicaSet struct {
+	replicas []*replica
+	cursor   atomic.Uint64
+}
+
+type replica struct {
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+func newReplicaSet(pools []*pgxpool.Pool) *replicaSet {
+	set := &replicaSet{replicas: make([]*replica, 0, len(pools))}
+	for _, pool := range pools {
+		r := &replica{pool: pool}
+		r.healthy.Store(true)
+		set.replicas = append(set.replicas, r)
+	}
+	return set
+}
+
+// next returns the next healthy replica, or nil if there is none.
+func (s *replicaSet) next() *pgxpool.Pool {
+	n := uint64(len(s.replicas))
+	if n == 0 {
+		return nil
+	}
+	start := s.cursor.Add(1)
+	for i := uint64(0); i < n; i++ {
+		if r := s.replicas[(start+i)%n]; r.healthy.Load() {
+			return r.pool
+		}
+	}
+	return nil
+}
+
+// CheckReplicas pings every replica each interval until ctx is done. A
+// replica that fails the ping gets no reads until it answers again. A
+// non-positive interval disables the checks.
+func (d *Database) CheckReplicas(ctx context.Context, interval time.Duration, timeout time.Duration) {
+	if len(d.replicas.replicas) == 0 || interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for i, r := range d.replicas.replicas {
+				pingCtx, cancel := context.WithTimeout(ctx, timeout)
+				err := r.pool.Ping(pingCtx)
+				cancel()
+				if healthy := err == nil; r.healthy.Swap(healthy) != healthy {
+					slog.Warn("Replica health changed", "replica", i, "healthy", healthy, "error", err)
+				}
+			}
+		}
+	}
+}
+
+// HealthyReplicas reports how many replicas currently take reads.
+func (d *Database) HealthyReplicas() (healthy int, total int) {
+	for _, r := range d.replicas.replicas {
+		if r.healthy.Load() {
+			healthy++
+		}
+	}
+	return healthy, len(d.replicas.replicas)
+}
@@ -0,0 +1,30 @@
+package pgdb
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestReplicaSetRoundRobin(t *testing.T) {
+	require.Nil(t, newReplicaSet(nil).next(), "no replicas fall back to the primary")
+
+	pools := []*pgxpool.Pool{{}, {}, {}}
+	set := newReplicaSet(pools)
+
+	seen := make(map[*pgxpool.Pool]int)
+	for i := 0; i < 6; i++ {
+		seen[set.next()]++
+	}
+	require.Equal(t, map[*pgxpool.Pool]int{pools[0]: 2, pools[1]: 2, pools[2]: 2}, seen)
+
+	set.replicas[1].healthy.Store(false)
+	for i := 0; i < 6; i++ {
+		require.NotSame(t, pools[1], set.next())
+	}
+
+	for _, r := range set.replicas {
+		r.healthy.Store(false)
+	}
+	require.Nil(t, set.next(), "reads go to the primary when no replica is healthy")
+}
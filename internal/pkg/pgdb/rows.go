@@ -0,0 +1,26 @@
+package pgdb
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+)
+
+// deadlineRows releases the query deadline once the rows are drained or
+// closed; the deadline has to outlive QuerySq as rows are read after it returns.
+type deadlineRows struct {
+	pgx.Rows
+	cancel context.CancelFunc
+}
+
+func (r *deadlineRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.cancel()
+	return false
+}
+
+func (r *deadlineRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+// evictSample is how many buckets are looked at to pick one to evict.
+const evictSample = 8
+
+var _ Limiter = (*MemoryLimiter)(nil)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+// MemoryLimiter is a process-local Limiter holding a bounded number of
+// buckets.
+type MemoryLimiter struct {
+	mu         sync.Mutex
+	buckets    map[string]*bucket
+	maxBuckets int
+	lastSweep  time.Time
+	now        func() time.Time
+}
+
+// NewMemoryLimiter returns new *MemoryLimiter that keeps at most maxBuckets
+// buckets.
+func NewMemoryLimiter(maxBuckets int) *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets:    make(map[string]*bucket),
+		maxBuckets: maxBuckets,
+		now:        time.Now,
+	}
+}
+
+func (l *MemoryLimiter) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	burst := limit.capacity()
+	if limit.Unlimited() {
+		return Result{Allowed: true, Limit: burst, Remaining: burst}, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= l.maxBuckets {
+			l.evict(now)
+		}
+		b = &bucket{tokens: float64(burst), last: now, limit: limit}
+		l.buckets[key] = b
+	}
+	b.refill(now, burst)
+	// Limits may change on config reload; the bucket follows from now on.
+	b.limit = limit
+
+	result := Result{Limit: burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / limit.Rate)
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = secondsToDuration((float64(burst) - b.tokens) / limit.Rate)
+	return result, nil
+}
+
+func (b *bucket) refill(now time.Time, burst int) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(burst), b.tokens+elapsed*b.limit.Rate)
+	}
+	b.last = now
+}
+
+// sweep drops buckets that have refilled completely: a full bucket behaves
+// exactly like a missing one, so this bounds memory without changing limits.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		burst := b.limit.capacity()
+		b.refill(now, burst)
+		if b.tokens >= float64(burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// evict drops the fullest of a few buckets picked at random to make room for
+// a new one. The fuller a bucket, the less forgetting it loosens its limit.
+func (l *MemoryLimiter) evict(now time.Time) {
+	var victim string
+	fullest := -1.0
+	seen := 0
+	for key, b := range l.buckets {
+		burst := b.limit.capacity()
+		b.refill(now, burst)
+		if fill := b.tokens / float64(burst); fill > fullest {
+			victim, fullest = key, fill
+		}
+		seen++
+		if seen == evictSample {
+			break
+		}
+	}
+	delete(l.buckets, victim)
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
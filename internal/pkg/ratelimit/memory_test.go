@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(maxBuckets int) (*MemoryLimiter, *time.Time) {
+	now := time.Now()
+	l := NewMemoryLimiter(maxBuckets)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestAllowBurstAndRefill(t *testing.T) {
+	l, now := newTestLimiter(10)
+	limit := Limit{Rate: 2, Burst: 3}
+
+	for i := 2; i >= 0; i-- {
+		result, err := l.Allow(context.Background(), "k", limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, 3, result.Limit)
+		require.Equal(t, i, result.Remaining)
+	}
+	result, err := l.Allow(context.Background(), "k", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 0, result.Remaining)
+	require.Equal(t, 500*time.Millisecond, result.RetryAfter, "one token comes back at 2 per second")
+	require.Equal(t, 1500*time.Millisecond, result.Reset, "three tokens come back at 2 per second")
+
+	*now = now.Add(time.Second)
+	result, err = l.Allow(context.Background(), "k", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, 1, result.Remaining)
+	require.Equal(t, time.Second, result.Reset)
+
+	*now = now.Add(time.Hour)
+	result, err = l.Allow(context.Background(), "k", limit)
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Remaining, "refill stops at the burst")
+}
+
+func TestAllowKeepsKeysApart(t *testing.T) {
+	l, _ := newTestLimiter(10)
+	limit := Limit{Rate: 1, Burst: 1}
+
+	result, _ := l.Allow(context.Background(), "a", limit)
+	require.True(t, result.Allowed)
+	result, _ = l.Allow(context.Background(), "a", limit)
+	require.False(t, result.Allowed)
+	result, _ = l.Allow(context.Background(), "b", limit)
+	require.True(t, result.Allowed)
+}
+
+func TestAllowUnlimited(t *testing.T) {
+	l, _ := newTestLimiter(10)
+	for i := 0; i < 5; i++ {
+		result, err := l.Allow(context.Background(), "k", Limit{})
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+	require.Empty(t, l.buckets)
+}
+
+func TestSweepDropsFullBuckets(t *testing.T) {
+	l, now := newTestLimiter(10)
+	limit := Limit{Rate: 1, Burst: 10}
+
+	_, _ = l.Allow(context.Background(), "idle", limit)
+	*now = now.Add(sweepInterval - time.Second)
+	for i := 0; i < 10; i++ {
+		_, _ = l.Allow(context.Background(), "busy", limit)
+	}
+	*now = now.Add(time.Second)
+	_, _ = l.Allow(context.Background(), "busy", limit)
+
+	require.NotContains(t, l.buckets, "idle")
+	require.Contains(t, l.buckets, "busy")
+}
+
+func TestMaxBuckets(t *testing.T) {
+	l, _ := newTestLimiter(evictSample)
+	limit := Limit{Rate: 1, Burst: 1}
+
+	for i := 0; i < 3*evictSample; i++ {
+		_, _ = l.Allow(context.Background(), fmt.Sprint("k", i), limit)
+		require.LessOrEqual(t, len(l.buckets), evictSample)
+	}
+}
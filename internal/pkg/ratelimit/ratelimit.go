@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Config represents rate limits for the user and admin route groups.
+type Config struct {
+	Enabled bool        `yaml:"enabled"`
+	User    GroupConfig `yaml:"user"`
+	Admin   GroupConfig `yaml:"admin"`
+	// MaxBuckets bounds how many clients are tracked at once.
+	MaxBuckets int `yaml:"maxBuckets"`
+}
+
+// GroupConfig holds the limits applied to a single route group. PerToken
+// applies to each authenticated principal.
+type GroupConfig struct {
+	PerToken Limit `yaml:"perToken"`
+	PerIP    Limit `yaml:"perIP"`
+}
+
+// Limit describes a token bucket: Rate tokens are added per second up to Burst.
+// A zero Rate disables the limit.
+type Limit struct {
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0
+}
+
+// capacity is the bucket size; a burst below one still lets one request in.
+func (l Limit) capacity() int {
+	if l.Burst < 1 {
+		return 1
+	}
+	return l.Burst
+}
+
+// Result is the outcome of a single Allow call.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Limiter takes one token for key from a bucket shaped by limit.
+// Implementations must be safe for concurrent use so that a shared store
+// can be plugged in instead of the in-memory one.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
@@ -0,0 +1,34 @@
+// Package tenancy carries the tenant a request acts for through contexts.
+// Storage refuses queries whose context carries no scope.
+package tenancy
+
+import "context"
+
+// Default is the tenant of tokens that do not name one and of data that
+// existed before tenants were introduced.
+const Default = "default"
+
+type scopeCtxKey struct{}
+
+// Scope is the set of tenants queries made with a context may touch.
+type Scope struct {
+	Tenant string
+	// All lifts tenant isolation. It is meant for background jobs only.
+	All bool
+}
+
+// WithTenant returns a copy of ctx scoped to tenant.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, scopeCtxKey{}, Scope{Tenant: tenant})
+}
+
+// WithAllTenants returns a copy of ctx that may touch data of every tenant.
+func WithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeCtxKey{}, Scope{All: true})
+}
+
+// FromContext returns the scope of ctx, if any.
+func FromContext(ctx context.Context) (Scope, bool) {
+	scope, ok := ctx.Value(scopeCtxKey{}).(Scope)
+	return scope, ok
+}
@@ -0,0 +1,55 @@
+package web
+
+import (
+	"expvar"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/pprof"
+)
+
+// Debug returns the router to mount diagnostic handlers on, with pprof and
+// expvar already under /debug, or nil when profiling is off. On the main
+// listener the routes are guarded by protect; a separate DebugListen address
+// is meant to be reachable internally only and is left open.
+func (s *BaseServer) Debug(protect ...gin.HandlerFunc) gin.IRouter {
+	if !s.config.Profile {
+		return nil
+	}
+	if s.debug != nil {
+		return s.debug
+	}
+	if s.config.DebugListen != "" {
+		engine := gin.New()
+		engine.Use(gin.Recovery())
+		s.debugServer = &http.Server{
+			Addr:              s.config.DebugListen,
+			Handler:           engine.Handler(),
+			ReadHeaderTimeout: s.config.ReadHeaderTimeout,
+		}
+		s.debug = engine.Group("/debug")
+	} else {
+		s.debug = s.engine.Group("/debug", protect...)
+	}
+	s.debug.GET("/vars", gin.WrapH(expvar.Handler()))
+	s.debug.GET("/pprof/*profile", servePprof)
+	s.debug.POST("/pprof/*profile", servePprof)
+	return s.debug
+}
+
+// servePprof dispatches like the default mux does for net/http/pprof, which
+// a gin catch-all route cannot express with separate routes.
+func servePprof(ctx *gin.Context) {
+	switch ctx.Param("profile") {
+	case "/cmdline":
+		pprof.Cmdline(ctx.Writer, ctx.Request)
+	case "/profile":
+		pprof.Profile(ctx.Writer, ctx.Request)
+	case "/symbol":
+		pprof.Symbol(ctx.Writer, ctx.Request)
+	case "/trace":
+		pprof.Trace(ctx.Writer, ctx.Request)
+	default:
+		// Index serves the named profiles too.
+		pprof.Index(ctx.Writer, ctx.Request)
+	}
+}
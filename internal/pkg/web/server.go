@@ -1,23 +1,39 @@
 package web
 
 import (
+	"avito-tech-backend/internal/pkg/health"
+	"avito-tech-backend/internal/pkg/logging"
 	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"log/slog"
 	"net/http"
 	"sync/atomic"
 	"time"
 )
 
+var errShuttingDown = errors.New("server is shutting down")
+
 // ServerConfig represents configuration for Server.
 type ServerConfig struct {
-	Listen            string        `config:"listen"`
-	DrainInterval     time.Duration `yaml:"drainInterval"`
-	Profile           bool          `yaml:"profile"`
+	Listen        string        `yaml:"listen"`
+	DrainInterval time.Duration `yaml:"drainInterval"`
+	// Profile mounts pprof and expvar handlers under /debug.
+	Profile bool `yaml:"profile"`
+	// DebugListen serves /debug on a separate address instead of the main
+	// one, where it requires admin auth.
+	DebugListen       string        `yaml:"debugListen"`
 	ReadTimeout       time.Duration `yaml:"readTimeout"`
 	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout"`
 	WriteTimeout      time.Duration `yaml:"writeTimeout"`
 	IdleTimeout       time.Duration `yaml:"idleTimeout"`
 	Env               string        `yaml:"env"`
+	// ReadinessTimeout bounds each check behind /ready.
+	ReadinessTimeout time.Duration `yaml:"readinessTimeout"`
+	TLS              TLSConfig     `yaml:"tls"`
+	// H2C serves HTTP/2 without TLS, for internal callers. It is ignored
+	// when TLS is on, as HTTP/2 is negotiated then.
+	H2C bool `yaml:"h2c"`
 }
 
 // Server is an interface for web http server.
@@ -26,6 +42,8 @@ type Server interface {
 	Shutdown(ctx context.Context) error
 	Router() gin.IRouter
 	Ready() bool
+	AddReadinessCheck(name string, check health.Check)
+	Debug(protect ...gin.HandlerFunc) gin.IRouter
 }
 
 var _ Server = (*BaseServer)(nil)
@@ -36,6 +54,10 @@ type BaseServer struct {
 	httpServer *http.Server
 
 	config ServerConfig
+	health *health.Checker
+
+	debug       *gin.RouterGroup
+	debugServer *http.Server
 
 	isNotReady int32
 }
@@ -45,8 +67,19 @@ func NewServer(config ServerConfig, handler *gin.Engine) *BaseServer {
 	s := &BaseServer{
 		engine: handler,
 		config: config,
+		health: health.NewChecker(config.ReadinessTimeout),
 	}
+	s.health.Register("server", func(context.Context) error {
+		if !s.Ready() {
+			return errShuttingDown
+		}
+		return nil
+	})
+	s.engine.GET("/live", func(_ *gin.Context) {})
+	s.engine.GET("/ping", s.getPing)
+	s.engine.GET("/ready", s.getReady)
 
+	s.engine.UseH2C = config.H2C && !config.TLS.Enabled()
 	s.httpServer = &http.Server{
 		Addr:              config.Listen,
 		Handler:           s.engine.Handler(),
@@ -60,9 +93,6 @@ func NewServer(config ServerConfig, handler *gin.Engine) *BaseServer {
 }
 
 func (s *BaseServer) Run(ctx context.Context) error {
-	s.Router().GET("/live", func(_ *gin.Context) {})
-	s.Router().GET("/ping", s.getPing)
-
 	go func() {
 		for {
 			<-ctx.Done()
@@ -70,13 +100,35 @@ func (s *BaseServer) Run(ctx context.Context) error {
 		}
 	}()
 
-	return s.httpServer.ListenAndServe()
+	if s.debugServer != nil {
+		go func() {
+			if err := s.debugServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("Debug server stopped", "error", err, "listen", s.config.DebugListen)
+			}
+		}()
+	}
+
+	if !s.config.TLS.Enabled() {
+		return s.httpServer.ListenAndServe()
+	}
+	tlsConfig, reloader, err := newTLSConfig(s.config.TLS)
+	if err != nil {
+		return err
+	}
+	go reloader.watch(ctx, s.config.TLS.ReloadInterval)
+	s.httpServer.TLSConfig = tlsConfig
+	return s.httpServer.ListenAndServeTLS("", "")
 }
 
+// Shutdown fails readiness checks and drains in-flight requests.
 func (s *BaseServer) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&s.isNotReady, 1)
 	ctx, cancel := context.WithTimeout(ctx, s.config.DrainInterval)
 	defer cancel()
 
+	if s.debugServer != nil {
+		_ = s.debugServer.Shutdown(ctx)
+	}
 	return s.httpServer.Shutdown(ctx)
 }
 
@@ -88,6 +140,28 @@ func (s *BaseServer) Ready() bool {
 	return atomic.LoadInt32(&s.isNotReady) == 0
 }
 
+// AddReadinessCheck makes /ready fail while check does.
+func (s *BaseServer) AddReadinessCheck(name string, check health.Check) {
+	s.health.Register(name, check)
+}
+
+// getReady runs every readiness check and reports each with its latency.
+// /ready is unauthenticated, so failed checks report a fixed reason and
+// their errors only go to the log.
+func (s *BaseServer) getReady(ctx *gin.Context) {
+	report := s.health.Run(ctx)
+	status := http.StatusOK
+	if report.Status != health.StatusOK {
+		status = http.StatusServiceUnavailable
+		for name, result := range report.Checks {
+			if result.Err != nil {
+				logging.FromContext(ctx).Warn("Readiness check failed", "check", name, "reason", result.Reason, "error", result.Err)
+			}
+		}
+	}
+	ctx.JSON(status, report)
+}
+
 func (s *BaseServer) getPing(ctx *gin.Context) {
 	if s.Ready() {
 		_, _ = ctx.Writer.Write([]byte("pong"))
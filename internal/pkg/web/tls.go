@@ -0,0 +1,177 @@
+package web
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// TLSConfig enables HTTPS when CertFile and KeyFile are set.
+type TLSConfig struct {
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+	// MinVersion is "1.2" or "1.3"; empty means 1.2.
+	MinVersion string `yaml:"minVersion"`
+	// ClientCAFile enables mutual TLS: client certificates signed by these
+	// CAs are verified when presented. Clients without one are still served.
+	ClientCAFile string `yaml:"clientCAFile"`
+	// ReloadInterval is how often the cert and key files are checked for
+	// changes; changed files are loaded without a restart.
+	ReloadInterval time.Duration `yaml:"reloadInterval"`
+}
+
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+// Validate reports a config that cannot be served, naming keys relative to
+// the TLS section.
+func (c TLSConfig) Validate() error {
+	if !c.Enabled() {
+		if c.ClientCAFile != "" {
+			return errors.New("clientCAFile requires certFile and keyFile")
+		}
+		return nil
+	}
+	if c.CertFile == "" || c.KeyFile == "" {
+		return errors.New("certFile and keyFile must be set together")
+	}
+	if _, err := ParseTLSVersion(c.MinVersion); err != nil {
+		return err
+	}
+	if c.ReloadInterval < 0 {
+		return errors.New("reloadInterval must not be negative")
+	}
+	return nil
+}
+
+func ParseTLSVersion(s string) (uint16, error) {
+	switch s {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("minVersion must be 1.2 or 1.3, got %q", s)
+	}
+}
+
+// newTLSConfig loads the certificate and client CAs and returns the server
+// TLS config along with the reloader serving its certificate.
+func newTLSConfig(cfg TLSConfig) (*tls.Config, *certReloader, error) {
+	minVersion, err := ParseTLSVersion(cfg.MinVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	reloader, err := newCertReloader(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:     minVersion,
+		GetCertificate: reloader.GetCertificate,
+	}
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, nil, fmt.Errorf("no certificates in client CA file %s", cfg.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsConfig, reloader, nil
+}
+
+// certReloader serves a certificate and picks up changes to its files.
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	modTimes [2]time.Time
+}
+
+func newCertReloader(certFile string, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// reload loads the files if they changed since the last load and reports
+// whether it did. A broken pair keeps the previous certificate in use.
+func (r *certReloader) reload() (bool, error) {
+	modTimes, err := r.stat()
+	if err != nil {
+		return false, err
+	}
+	r.mu.RLock()
+	unchanged := r.cert != nil && modTimes == r.modTimes
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("load certificate: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.modTimes = modTimes
+	return true, nil
+}
+
+func (r *certReloader) stat() ([2]time.Time, error) {
+	var modTimes [2]time.Time
+	for i, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return modTimes, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+// watch reloads the certificate each interval until ctx is done.
+func (r *certReloader) watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		reloaded, err := r.reload()
+		if err != nil {
+			slog.Error("Error with reloading TLS certificate, keeping the current one", "error", err, "cert", r.certFile)
+			continue
+		}
+		if reloaded {
+			slog.Info("Reloaded TLS certificate", "cert", r.certFile)
+		}
+	}
+}
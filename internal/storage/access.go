@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"avito-tech-backend/internal/pkg/access"
+	sq "github.com/Masterminds/squirrel"
+	"strings"
+)
+
+// bannerAccess matches banners the scope allows: of a listed feature and
+// with every tag inside the tag ranges.
+func bannerAccess(scope access.Scope) sq.Sqlizer {
+	filter := sq.And{}
+	if len(scope.FeatureIds) != 0 {
+		filter = append(filter, sq.Eq{"feature_id": scope.FeatureIds})
+	}
+	if len(scope.TagRanges) != 0 {
+		ranges, args := rangesExpr("t", scope.TagRanges)
+		filter = append(filter, sq.Expr("NOT EXISTS (SELECT 1 FROM unnest(tag_ids) AS t WHERE NOT ("+ranges+"))", args...))
+	}
+	return filter
+}
+
+func featureAccess(scope access.Scope) sq.Sqlizer {
+	if len(scope.FeatureIds) == 0 {
+		return sq.And{}
+	}
+	return sq.Eq{"id": scope.FeatureIds}
+}
+
+func tagAccess(scope access.Scope) sq.Sqlizer {
+	if len(scope.TagRanges) == 0 {
+		return sq.And{}
+	}
+	ranges, args := rangesExpr("id", scope.TagRanges)
+	return sq.Expr("("+ranges+")", args...)
+}
+
+func rangesExpr(column string, ranges []access.Range) (string, []any) {
+	conditions := make([]string, 0, len(ranges))
+	args := make([]any, 0, 2*len(ranges))
+	for _, r := range ranges {
+		conditions = append(conditions, column+" BETWEEN ? AND ?")
+		args = append(args, r.From, r.To)
+	}
+	return strings.Join(conditions, " OR "), args
+}
@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"avito-tech-backend/internal/pkg/access"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAccessFilters(t *testing.T) {
+	scope := access.Scope{
+		FeatureIds: []int64{1, 2},
+		TagRanges:  []access.Range{{From: 10, To: 19}, {From: 30, To: 30}},
+	}
+
+	query, args, err := sq.Select("id").From("banners").Where(bannerAccess(scope)).PlaceholderFormat(sq.Dollar).ToSql()
+	require.NoError(t, err)
+	require.Equal(t, "SELECT id FROM banners WHERE (feature_id IN ($1,$2) AND NOT EXISTS (SELECT 1 FROM unnest(tag_ids) AS t WHERE NOT (t BETWEEN $3 AND $4 OR t BETWEEN $5 AND $6)))", query)
+	require.Equal(t, []any{int64(1), int64(2), int64(10), int64(19), int64(30), int64(30)}, args)
+
+	query, args, err = sq.Select("id").From("tags").Where(tagAccess(scope)).PlaceholderFormat(sq.Dollar).ToSql()
+	require.NoError(t, err)
+	require.Equal(t, "SELECT id FROM tags WHERE (id BETWEEN $1 AND $2 OR id BETWEEN $3 AND $4)", query)
+	require.Len(t, args, 4)
+
+	query, _, err = sq.Select("id").From("banners").Where(bannerAccess(access.Scope{})).ToSql()
+	require.NoError(t, err)
+	require.Equal(t, "SELECT id FROM banners WHERE (1=1)", query)
+}
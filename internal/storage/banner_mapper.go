@@ -2,18 +2,29 @@ package storage
 
 import (
 	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/core/targeting"
+	"avito-tech-backend/internal/pkg/access"
 	"context"
+	"encoding/json"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v4"
-	"strconv"
 	"time"
 )
 
+var bannerColumns = []string{"id", "tag_ids", "feature_id", "content", "is_active", "created_at", "updated_at", "version", "deleted_at", "priority", "targeting", "localized_content", "default_locale", "frequency_cap", "tenant"}
+
 type BannerCreateParams struct {
 	TagIds    []int64
 	FeatureId int64
 	Content   string
 	IsActive  bool
+	Priority  int64
+	Targeting *targeting.Rules
+
+	FrequencyCap int64
+
+	LocalizedContent map[string]string
+	DefaultLocale    string
 }
 
 type BannerMapper struct {
@@ -25,9 +36,6 @@ func (m *BannerMapper) executeQuery(ctx context.Context, query sq.Sqlizer) ([]en
 	if err != nil {
 		return nil, err
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
 	defer rows.Close()
 	result := make([]entities.Banner, 0)
 	for rows.Next() {
@@ -37,50 +45,144 @@ func (m *BannerMapper) executeQuery(ctx context.Context, query sq.Sqlizer) ([]en
 		}
 		result = append(result, banner)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 func toBanner(rows pgx.Rows) (entities.Banner, error) {
 	var banner entities.Banner
-	err := rows.Scan(&banner.ID, &banner.TagIds, &banner.FeatureId, &banner.Content, &banner.IsActive, &banner.CreatedAt, &banner.UpdatedAt)
+	err := rows.Scan(&banner.ID, &banner.TagIds, &banner.FeatureId, &banner.Content, &banner.IsActive, &banner.CreatedAt, &banner.UpdatedAt, &banner.Version, &banner.DeletedAt, &banner.Priority, &banner.Targeting, &banner.LocalizedContent, &banner.DefaultLocale, &banner.FrequencyCap, &banner.Tenant)
 	if err != nil {
 		return entities.Banner{}, err
 	}
 	return banner, nil
 }
 
-func (m *BannerMapper) GetAllBannersByTagAndOrFeature(ctx context.Context, tagId int64, featureId int64, limit uint64, offset uint64) ([]entities.Banner, error) {
-	return m.executeQuery(ctx, sq.Select("*").From("banners").
-		PlaceholderFormat(sq.Dollar).
-		Where(sq.And{sq.Eq{"tag_ids": tagId}, sq.Eq{"feature_id": featureId}}).
-		Limit(limit).Offset(offset))
+// GetAllBannersByTagAndOrFeature lists live banners the scope allows,
+// optionally narrowed to a tag and/or a feature.
+func (m *BannerMapper) GetAllBannersByTagAndOrFeature(ctx context.Context, tagId int64, featureId int64, scope access.Scope, limit uint64, offset uint64) ([]entities.Banner, error) {
+	q, err := selectScoped(ctx, "banners", bannerColumns)
+	if err != nil {
+		return nil, err
+	}
+	q = q.Where(sq.Eq{"deleted_at": nil}).
+		Where(bannerAccess(scope)).
+		OrderBy("id").
+		Limit(limit).Offset(offset)
+	if tagId != 0 {
+		q = q.Where("tag_ids @> ARRAY[?]::integer[]", tagId)
+	}
+	if featureId != 0 {
+		q = q.Where(sq.Eq{"feature_id": featureId})
+	}
+	return m.executeQuery(ctx, q)
 }
 
-func (m *BannerMapper) GetBannerByTagAndFeature(ctx context.Context, featureId int64, tagId int64, useLastVersion bool) (*entities.Banner, error) {
-	q := sq.Select("*").From("banners").
-		PlaceholderFormat(sq.Dollar)
-	if useLastVersion {
-		q.Where(sq.And{sq.Eq{"feature_id": featureId}, sq.Eq{"tag_ids": tagId}, sq.GtOrEq{"updated_at": time.Now().Add(-time.Minute * 5)}}).
-			OrderBy("updated_at DESC").Limit(1)
-	} else {
-		q.Where(sq.And{sq.Eq{"feature_id": featureId}, sq.Eq{"tag_ids": tagId}})
+// GetActiveBannersByTagAndFeature returns active banners for the tag and
+// feature, highest priority first.
+func (m *BannerMapper) GetActiveBannersByTagAndFeature(ctx context.Context, tagId int64, featureId int64) ([]entities.Banner, error) {
+	q, err := selectScoped(ctx, "banners", bannerColumns)
+	if err != nil {
+		return nil, err
 	}
-	q.Where(sq.Eq{"is_active": true})
-	result, err := m.executeQuery(ctx, q)
+	return m.executeQuery(ctx, q.
+		Where(sq.Eq{"feature_id": featureId, "is_active": true, "deleted_at": nil}).
+		Where("tag_ids @> ARRAY[?]::integer[]", tagId).
+		OrderBy("priority DESC", "id DESC"))
+}
+
+// GetActiveBanners returns every active banner, highest priority first.
+func (m *BannerMapper) GetActiveBanners(ctx context.Context) ([]entities.Banner, error) {
+	q, err := selectScoped(ctx, "banners", bannerColumns)
 	if err != nil {
 		return nil, err
 	}
-	if len(result) == 0 {
-		return nil, nil
+	return m.executeQuery(ctx, q.
+		Where(sq.Eq{"is_active": true, "deleted_at": nil}).
+		OrderBy("priority DESC", "id DESC"))
+}
+
+// GetActiveBannersForPairs returns active banners shown for any of the
+// pairs, highest priority first.
+func (m *BannerMapper) GetActiveBannersForPairs(ctx context.Context, pairs []BannerPair) ([]entities.Banner, error) {
+	q, err := selectScoped(ctx, "banners", bannerColumns)
+	if err != nil {
+		return nil, err
 	}
-	return &result[0], nil
+	tenants := make([]string, 0, len(pairs))
+	tagIds := make([]int64, 0, len(pairs))
+	featureIds := make([]int64, 0, len(pairs))
+	for _, pair := range pairs {
+		tenants = append(tenants, pair.Tenant)
+		tagIds = append(tagIds, pair.TagId)
+		featureIds = append(featureIds, pair.FeatureId)
+	}
+	return m.executeQuery(ctx, q.
+		Where(sq.Eq{"is_active": true, "deleted_at": nil}).
+		Where("EXISTS (SELECT 1 FROM unnest(?::text[], ?::integer[], ?::integer[]) AS p(tenant, tag_id, feature_id) "+
+			"WHERE p.tenant = banners.tenant AND p.feature_id = banners.feature_id AND p.tag_id = ANY(banners.tag_ids))",
+			tenants, tagIds, featureIds).
+		OrderBy("priority DESC", "id DESC"))
+}
+
+// BannerPair is a (tag, feature) pair users request banners for.
+type BannerPair struct {
+	Tenant    string
+	TagId     int64
+	FeatureId int64
+}
+
+// BannerPairStats describes a pair that has active banners.
+type BannerPairStats struct {
+	BannerPair
+	// Requests counts user banner requests for the pair since the given day.
+	Requests int64
+	// Priority is the highest priority among the pair's banners.
+	Priority int64
+}
+
+// GetBannerPairStats returns every pair that has active banners along with
+// its requests since the given day.
+func (m *BannerMapper) GetBannerPairStats(ctx context.Context, since time.Time) ([]BannerPairStats, error) {
+	q, err := selectScoped(ctx,
+		"banners b CROSS JOIN LATERAL unnest(b.tag_ids) AS t(tag_id)",
+		[]string{"b.tenant", "t.tag_id", "b.feature_id", "MAX(b.priority)"})
+	if err != nil {
+		return nil, err
+	}
+	rows, err := m.Storage.Database.QuerySq(ctx, q.
+		Column("COALESCE((SELECT SUM(r.count) FROM banner_requests r "+
+			"WHERE r.tenant = b.tenant AND r.tag_id = t.tag_id AND r.feature_id = b.feature_id AND r.day >= ?), 0)::bigint", since).
+		Where(sq.Eq{"b.is_active": true, "b.deleted_at": nil}).
+		GroupBy("b.tenant", "t.tag_id", "b.feature_id"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	stats := make([]BannerPairStats, 0)
+	for rows.Next() {
+		var pair BannerPairStats
+		if err := rows.Scan(&pair.Tenant, &pair.TagId, &pair.FeatureId, &pair.Priority, &pair.Requests); err != nil {
+			return nil, err
+		}
+		stats = append(stats, pair)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
 }
 
 func (m *BannerMapper) InsertBanner(ctx context.Context, params BannerCreateParams) (*entities.Banner, error) {
-	result, err := m.executeQuery(ctx, sq.Insert("banners").
-		PlaceholderFormat(sq.Dollar).
-		Columns("tag_ids", "feature_id", "content", "is_active", "created_at", "updated_at").
-		Values(params.TagIds, params.FeatureId, params.Content, params.IsActive, time.Now(), time.Now()).
-		Suffix("RETURNING *"))
+	q, err := insertScoped(ctx, "banners",
+		[]string{"tag_ids", "feature_id", "content", "is_active", "priority", "targeting", "localized_content", "default_locale", "frequency_cap", "created_at", "updated_at"},
+		params.TagIds, params.FeatureId, params.Content, params.IsActive, params.Priority, targetingValue(params.Targeting),
+		localizedContentValue(params.LocalizedContent), params.DefaultLocale, params.FrequencyCap, time.Now(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.executeQuery(ctx, q.Suffix("RETURNING "+columnList(bannerColumns)))
 	if err != nil {
 		return nil, err
 	}
@@ -91,29 +193,46 @@ func (m *BannerMapper) InsertBanner(ctx context.Context, params BannerCreatePara
 }
 
 func (m *BannerMapper) UpdateBannerById(ctx context.Context, id int64, params entities.RawBanner) (*entities.Banner, error) {
-	q := sq.Update("banners").PlaceholderFormat(sq.Dollar)
+	q, err := updateScoped(ctx, "banners")
+	if err != nil {
+		return nil, err
+	}
 	if params.TagIds != nil {
-		q.Set("tag_ids", params.TagIds)
+		q = q.Set("tag_ids", *params.TagIds)
 	}
-	if params.FeatureId != "null" {
-		featureId, err := strconv.ParseInt(params.FeatureId, 10, 0)
-		if err != nil {
-			return nil, err
-		}
-		q.Set("feature_id", featureId)
+	if params.FeatureId != nil {
+		q = q.Set("feature_id", *params.FeatureId)
 	}
-	if params.Content != "null" {
-		q.Set("content", params.Content)
+	if params.Content != nil {
+		q = q.Set("content", *params.Content)
 	}
-	if params.IsActive != "null" {
-		isActive, err := strconv.ParseBool(params.IsActive)
-		if err != nil {
-			return nil, err
-		}
-		q.Set("is_active", isActive)
+	if params.IsActive != nil {
+		q = q.Set("is_active", *params.IsActive)
+	}
+	if params.Priority != nil {
+		q = q.Set("priority", *params.Priority)
+	}
+	if params.Targeting != nil {
+		q = q.Set("targeting", targetingValue(params.Targeting))
+	}
+	if params.LocalizedContent != nil {
+		q = q.Set("localized_content", localizedContentValue(*params.LocalizedContent))
+	}
+	if params.DefaultLocale != nil {
+		q = q.Set("default_locale", *params.DefaultLocale)
+	}
+	if params.FrequencyCap != nil {
+		q = q.Set("frequency_cap", *params.FrequencyCap)
+	}
+	q = q.Set("updated_at", time.Now()).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": id, "deleted_at": nil})
+	if params.ExpectedVersion != nil {
+		// Comparing versions in the same statement leaves no window for a
+		// concurrent writer between the check and the update.
+		q = q.Where(sq.Eq{"version": *params.ExpectedVersion})
 	}
-	q.Set("updated_at", time.Now())
-	q.Where(sq.Eq{"id": id})
+	q = q.Suffix("RETURNING " + columnList(bannerColumns))
 	result, err := m.executeQuery(ctx, q)
 	if err != nil {
 		return nil, err
@@ -124,10 +243,16 @@ func (m *BannerMapper) UpdateBannerById(ctx context.Context, id int64, params en
 	return &result[0], nil
 }
 
+// DeleteBannerById moves the banner to the trash.
 func (m *BannerMapper) DeleteBannerById(ctx context.Context, id int64) (*entities.Banner, error) {
-	result, err := m.executeQuery(ctx, sq.Delete("banners").
-		PlaceholderFormat(sq.Dollar).
-		Where(sq.Eq{"id": id}))
+	q, err := updateScoped(ctx, "banners")
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.executeQuery(ctx, q.
+		Set("deleted_at", time.Now()).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		Suffix("RETURNING "+columnList(bannerColumns)))
 	if err != nil {
 		return nil, err
 	}
@@ -137,10 +262,53 @@ func (m *BannerMapper) DeleteBannerById(ctx context.Context, id int64) (*entitie
 	return &result[0], nil
 }
 
+func (m *BannerMapper) RestoreBannerById(ctx context.Context, id int64) (*entities.Banner, error) {
+	q, err := updateScoped(ctx, "banners")
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.executeQuery(ctx, q.
+		Set("deleted_at", nil).
+		Where(sq.And{sq.Eq{"id": id}, sq.NotEq{"deleted_at": nil}}).
+		Suffix("RETURNING "+columnList(bannerColumns)))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+func (m *BannerMapper) GetDeletedBanners(ctx context.Context, scope access.Scope, limit uint64, offset uint64) ([]entities.Banner, error) {
+	q, err := selectScoped(ctx, "banners", bannerColumns)
+	if err != nil {
+		return nil, err
+	}
+	return m.executeQuery(ctx, q.
+		Where(sq.NotEq{"deleted_at": nil}).
+		Where(bannerAccess(scope)).
+		OrderBy("deleted_at DESC").
+		Limit(limit).Offset(offset))
+}
+
+// PurgeDeletedBanners hard-deletes banners trashed before the given moment.
+func (m *BannerMapper) PurgeDeletedBanners(ctx context.Context, before time.Time) ([]entities.Banner, error) {
+	q, err := deleteScoped(ctx, "banners")
+	if err != nil {
+		return nil, err
+	}
+	return m.executeQuery(ctx, q.
+		Where(sq.Lt{"deleted_at": before}).
+		Suffix("RETURNING "+columnList(bannerColumns)))
+}
+
 func (m *BannerMapper) FindBannerById(ctx context.Context, id int64) (*entities.Banner, error) {
-	result, err := m.executeQuery(ctx, sq.Select("*").From("banners").
-		PlaceholderFormat(sq.Dollar).
-		Where(sq.Eq{"id": id}))
+	q, err := selectScoped(ctx, "banners", bannerColumns)
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.executeQuery(ctx, q.Where(sq.Eq{"id": id, "deleted_at": nil}))
 	if err != nil {
 		return nil, err
 	}
@@ -150,17 +318,12 @@ func (m *BannerMapper) FindBannerById(ctx context.Context, id int64) (*entities.
 	return &result[0], nil
 }
 
-func (m *BannerMapper) FindBannerByTagIdAndFeatureIdAndUseLastVersion(ctx context.Context, tagId int64, featureId int64, useLastVersion bool) (*entities.Banner, error) {
-	q := sq.Select("*").From("banners").
-		PlaceholderFormat(sq.Dollar)
-	if useLastVersion {
-		q.Where(sq.And{sq.Eq{"feature_id": featureId}, sq.Eq{"tag_ids": tagId}, sq.GtOrEq{"updated_at": time.Now().Add(-time.Minute * 5)}}).
-			OrderBy("updated_at DESC").Limit(1)
-	} else {
-		q.Where(sq.And{sq.Eq{"feature_id": featureId}, sq.Eq{"tag_ids": tagId}})
+func (m *BannerMapper) FindDeletedBannerById(ctx context.Context, id int64) (*entities.Banner, error) {
+	q, err := selectScoped(ctx, "banners", bannerColumns)
+	if err != nil {
+		return nil, err
 	}
-	q.Where(sq.Eq{"is_active": true})
-	result, err := m.executeQuery(ctx, q)
+	result, err := m.executeQuery(ctx, q.Where(sq.And{sq.Eq{"id": id}, sq.NotEq{"deleted_at": nil}}))
 	if err != nil {
 		return nil, err
 	}
@@ -169,3 +332,48 @@ func (m *BannerMapper) FindBannerByTagIdAndFeatureIdAndUseLastVersion(ctx contex
 	}
 	return &result[0], nil
 }
+
+func (m *BannerMapper) ExistsBannerByFeatureId(ctx context.Context, featureId int64) (bool, error) {
+	q, err := selectScoped(ctx, "banners", bannerColumns)
+	if err != nil {
+		return false, err
+	}
+	result, err := m.executeQuery(ctx, q.
+		Where(sq.Eq{"feature_id": featureId}).
+		Limit(1))
+	if err != nil {
+		return false, err
+	}
+	return len(result) != 0, nil
+}
+
+func (m *BannerMapper) ExistsBannerByTagId(ctx context.Context, tagId int64) (bool, error) {
+	q, err := selectScoped(ctx, "banners", bannerColumns)
+	if err != nil {
+		return false, err
+	}
+	result, err := m.executeQuery(ctx, q.
+		Where("tag_ids @> ARRAY[?]::integer[]", tagId).
+		Limit(1))
+	if err != nil {
+		return false, err
+	}
+	return len(result) != 0, nil
+}
+
+// targetingValue encodes rules for a jsonb column, keeping absent rules NULL.
+func targetingValue(rules *targeting.Rules) any {
+	if rules == nil {
+		return nil
+	}
+	value, _ := json.Marshal(rules)
+	return value
+}
+
+func localizedContentValue(content map[string]string) any {
+	if len(content) == 0 {
+		return nil
+	}
+	value, _ := json.Marshal(content)
+	return value
+}
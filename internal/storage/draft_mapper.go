@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"avito-tech-backend/internal/core/entities"
+	"context"
+	"encoding/json"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+	"time"
+)
+
+var (
+	draftColumns   = []string{"id", "banner_id", "changes", "base_version", "status", "author", "reviewer", "created_at", "updated_at", "tenant"}
+	commentColumns = []string{"id", "draft_id", "author", "action", "body", "created_at"}
+)
+
+type DraftCreateParams struct {
+	BannerId    int64
+	Changes     entities.RawBanner
+	BaseVersion int64
+	Author      string
+}
+
+type DraftMapper struct {
+	Storage *Storage
+}
+
+func (m *DraftMapper) executeQuery(ctx context.Context, query sq.Sqlizer) ([]entities.BannerDraft, error) {
+	rows, err := m.Storage.Database.QuerySq(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make([]entities.BannerDraft, 0)
+	for rows.Next() {
+		draft, err := toDraft(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, draft)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+func toDraft(rows pgx.Rows) (entities.BannerDraft, error) {
+	var draft entities.BannerDraft
+	var changes []byte
+	err := rows.Scan(&draft.ID, &draft.BannerId, &changes, &draft.BaseVersion, &draft.Status, &draft.Author, &draft.Reviewer, &draft.CreatedAt, &draft.UpdatedAt, &draft.Tenant)
+	if err != nil {
+		return entities.BannerDraft{}, err
+	}
+	if err := json.Unmarshal(changes, &draft.Changes); err != nil {
+		return entities.BannerDraft{}, err
+	}
+	draft.Changes.ID = draft.BannerId
+	return draft, nil
+}
+
+func (m *DraftMapper) executeCommentQuery(ctx context.Context, query sq.Sqlizer) ([]entities.DraftComment, error) {
+	rows, err := m.Storage.Database.QuerySq(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make([]entities.DraftComment, 0)
+	for rows.Next() {
+		var comment entities.DraftComment
+		if err := rows.Scan(&comment.ID, &comment.DraftId, &comment.Author, &comment.Action, &comment.Body, &comment.CreatedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (m *DraftMapper) InsertDraft(ctx context.Context, params DraftCreateParams) (*entities.BannerDraft, error) {
+	changes, err := json.Marshal(params.Changes)
+	if err != nil {
+		return nil, err
+	}
+	q, err := insertScoped(ctx, "banner_drafts",
+		[]string{"banner_id", "changes", "base_version", "status", "author", "created_at", "updated_at"},
+		params.BannerId, changes, params.BaseVersion, entities.DraftStatusDraft, params.Author, time.Now(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.executeQuery(ctx, q.Suffix("RETURNING "+columnList(draftColumns)))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+func (m *DraftMapper) FindDraftById(ctx context.Context, id int64) (*entities.BannerDraft, error) {
+	q, err := selectScoped(ctx, "banner_drafts", draftColumns)
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.executeQuery(ctx, q.Where(sq.Eq{"id": id}))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+func (m *DraftMapper) GetDraftsByBannerId(ctx context.Context, bannerId int64) ([]entities.BannerDraft, error) {
+	q, err := selectScoped(ctx, "banner_drafts", draftColumns)
+	if err != nil {
+		return nil, err
+	}
+	return m.executeQuery(ctx, q.
+		Where(sq.Eq{"banner_id": bannerId}).
+		OrderBy("id DESC"))
+}
+
+// UpdateDraftStatus moves the draft from one status to another. It returns
+// nil if the draft is not in the expected status anymore.
+func (m *DraftMapper) UpdateDraftStatus(ctx context.Context, id int64, from entities.DraftStatus, to entities.DraftStatus, reviewer *string) (*entities.BannerDraft, error) {
+	q, err := updateScoped(ctx, "banner_drafts")
+	if err != nil {
+		return nil, err
+	}
+	q = q.Set("status", to).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": id, "status": from})
+	if reviewer != nil {
+		q = q.Set("reviewer", *reviewer)
+	}
+	result, err := m.executeQuery(ctx, q.Suffix("RETURNING "+columnList(draftColumns)))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+func (m *DraftMapper) InsertComment(ctx context.Context, comment entities.DraftComment) (*entities.DraftComment, error) {
+	result, err := m.executeCommentQuery(ctx, sq.Insert("banner_draft_comments").
+		PlaceholderFormat(sq.Dollar).
+		Columns("draft_id", "author", "action", "body", "created_at").
+		Values(comment.DraftId, comment.Author, comment.Action, comment.Body, time.Now()).
+		Suffix("RETURNING "+columnList(commentColumns)))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+func (m *DraftMapper) GetCommentsByDraftIds(ctx context.Context, draftIds []int64) ([]entities.DraftComment, error) {
+	return m.executeCommentQuery(ctx, sq.Select(commentColumns...).From("banner_draft_comments").
+		PlaceholderFormat(sq.Dollar).
+		Where(sq.Eq{"draft_id": draftIds}).
+		OrderBy("id"))
+}
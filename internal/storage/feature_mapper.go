@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/pkg/access"
+	"context"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+	"time"
+)
+
+var featureColumns = []string{"id", "name", "description", "owner", "is_archived", "created_at", "updated_at", "tenant"}
+
+type FeatureCreateParams struct {
+	Name        string
+	Description string
+	Owner       string
+}
+
+type FeatureMapper struct {
+	Storage *Storage
+}
+
+func (m *FeatureMapper) executeQuery(ctx context.Context, query sq.Sqlizer) ([]entities.Feature, error) {
+	rows, err := m.Storage.Database.QuerySq(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make([]entities.Feature, 0)
+	for rows.Next() {
+		feature, err := toFeature(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, feature)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+func toFeature(rows pgx.Rows) (entities.Feature, error) {
+	var feature entities.Feature
+	err := rows.Scan(&feature.ID, &feature.Name, &feature.Description, &feature.Owner, &feature.IsArchived, &feature.CreatedAt, &feature.UpdatedAt, &feature.Tenant)
+	if err != nil {
+		return entities.Feature{}, err
+	}
+	return feature, nil
+}
+
+func (m *FeatureMapper) GetAllFeatures(ctx context.Context, includeArchived bool, scope access.Scope, limit uint64, offset uint64) ([]entities.Feature, error) {
+	q, err := selectScoped(ctx, "features", featureColumns)
+	if err != nil {
+		return nil, err
+	}
+	q = q.Where(featureAccess(scope)).
+		OrderBy("id").
+		Limit(limit).Offset(offset)
+	if !includeArchived {
+		q = q.Where(sq.Eq{"is_archived": false})
+	}
+	return m.executeQuery(ctx, q)
+}
+
+func (m *FeatureMapper) FindFeatureById(ctx context.Context, id int64) (*entities.Feature, error) {
+	q, err := selectScoped(ctx, "features", featureColumns)
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.executeQuery(ctx, q.Where(sq.Eq{"id": id}))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+func (m *FeatureMapper) FindFeaturesByIds(ctx context.Context, ids []int64) ([]entities.Feature, error) {
+	q, err := selectScoped(ctx, "features", featureColumns)
+	if err != nil {
+		return nil, err
+	}
+	return m.executeQuery(ctx, q.Where(sq.Eq{"id": ids}))
+}
+
+func (m *FeatureMapper) InsertFeature(ctx context.Context, params FeatureCreateParams) (*entities.Feature, error) {
+	q, err := insertScoped(ctx, "features",
+		[]string{"name", "description", "owner", "created_at", "updated_at"},
+		params.Name, params.Description, params.Owner, time.Now(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.executeQuery(ctx, q.Suffix("RETURNING "+columnList(featureColumns)))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+func (m *FeatureMapper) UpdateFeatureById(ctx context.Context, id int64, params entities.RawFeature) (*entities.Feature, error) {
+	q, err := updateScoped(ctx, "features")
+	if err != nil {
+		return nil, err
+	}
+	if params.Name != nil {
+		q = q.Set("name", *params.Name)
+	}
+	if params.Description != nil {
+		q = q.Set("description", *params.Description)
+	}
+	if params.Owner != nil {
+		q = q.Set("owner", *params.Owner)
+	}
+	if params.IsArchived != nil {
+		q = q.Set("is_archived", *params.IsArchived)
+	}
+	q = q.Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING " + columnList(featureColumns))
+	result, err := m.executeQuery(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+func (m *FeatureMapper) DeleteFeatureById(ctx context.Context, id int64) (*entities.Feature, error) {
+	q, err := deleteScoped(ctx, "features")
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.executeQuery(ctx, q.
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING "+columnList(featureColumns)))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"avito-tech-backend/internal/pkg/impressions"
+	"avito-tech-backend/internal/pkg/pgdb"
+	"context"
+	sq "github.com/Masterminds/squirrel"
+	"time"
+)
+
+var _ impressions.Store = (*ImpressionMapper)(nil)
+
+type ImpressionMapper struct {
+	Storage *Storage
+}
+
+// Take counts an impression unless the cap is already reached. The check
+// and the increment happen in one statement, so concurrent instances cannot
+// overshoot the cap.
+func (m *ImpressionMapper) Take(ctx context.Context, key impressions.Key, limit int64) (bool, error) {
+	rows, err := m.Storage.Database.QuerySq(ctx, sq.Insert("banner_impressions").
+		PlaceholderFormat(sq.Dollar).
+		Columns("user_id", "banner_id", "day", "count").
+		Values(key.UserId, key.BannerId, key.Day, 1).
+		Suffix("ON CONFLICT (user_id, banner_id, day) DO UPDATE SET count = banner_impressions.count + 1 "+
+			"WHERE banner_impressions.count < ? RETURNING count", limit))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	taken := rows.Next()
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+	return taken, nil
+}
+
+func (m *ImpressionMapper) Allows(ctx context.Context, key impressions.Key, limit int64) (bool, error) {
+	// Counters change with every impression; a replica would lag behind.
+	rows, err := m.Storage.Database.QuerySq(pgdb.WithPrimary(ctx), sq.Select("1").
+		From("banner_impressions").
+		PlaceholderFormat(sq.Dollar).
+		Where(sq.Eq{"user_id": key.UserId, "banner_id": key.BannerId, "day": key.Day}).
+		Where(sq.GtOrEq{"count": limit}))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	reached := rows.Next()
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+	return !reached, nil
+}
+
+// PurgeImpressions deletes counters of days before the given one.
+func (m *ImpressionMapper) PurgeImpressions(ctx context.Context, before time.Time) (int64, error) {
+	rows, err := m.Storage.Database.QuerySq(ctx, sq.Delete("banner_impressions").
+		PlaceholderFormat(sq.Dollar).
+		Where(sq.Lt{"day": before}).
+		Suffix("RETURNING 1"))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	var purged int64
+	for rows.Next() {
+		purged++
+	}
+	return purged, rows.Err()
+}
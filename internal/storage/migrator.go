@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"avito-tech-backend/internal/pkg/pgdb"
+	"avito-tech-backend/migrations"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"io/fs"
+	"sync"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+)
+
+// MigrationStatus describes the schema version of the database.
+type MigrationStatus struct {
+	// Version is the last applied migration, 0 if none was applied.
+	Version uint `json:"version"`
+	// Dirty is set when a migration failed halfway and has to be fixed by hand.
+	Dirty bool `json:"dirty"`
+	// Latest is the newest migration embedded in the binary.
+	Latest uint `json:"latest"`
+}
+
+// CheckMigrations fails unless the database schema has every migration
+// embedded in the binary applied. It reads the version directly and does not
+// take the migration lock.
+func (s *Storage) CheckMigrations(ctx context.Context) error {
+	latest, err := embeddedLatest()
+	if err != nil {
+		return err
+	}
+	rows, err := s.Database.QuerySq(pgdb.WithPrimary(ctx), sq.Select("version", "dirty").From("schema_migrations").Limit(1))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	var status MigrationStatus
+	if rows.Next() {
+		if err := rows.Scan(&status.Version, &status.Dirty); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	switch {
+	case status.Dirty:
+		return fmt.Errorf("migration %d is dirty", status.Version)
+	case status.Version < latest:
+		return fmt.Errorf("schema is at version %d, expected %d", status.Version, latest)
+	}
+	return nil
+}
+
+// Migrator applies the migrations embedded in the binary.
+type Migrator struct {
+	migrate *migrate.Migrate
+	latest  uint
+}
+
+func NewMigrator(cfg Config) (*Migrator, error) {
+	latest, err := embeddedLatest()
+	if err != nil {
+		return nil, err
+	}
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("pgx", cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		_ = driver.Close()
+		return nil, err
+	}
+	return &Migrator{migrate: m, latest: latest}, nil
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up() error {
+	return ignoreNoChange(m.migrate.Up())
+}
+
+// Down rolls back the given number of applied migrations.
+func (m *Migrator) Down(steps int) error {
+	return ignoreNoChange(m.migrate.Steps(-steps))
+}
+
+// Goto migrates up or down to the given version.
+func (m *Migrator) Goto(version uint) error {
+	return ignoreNoChange(m.migrate.Migrate(version))
+}
+
+// Force records version as applied and clears the dirty flag without running
+// any migration. Version -1 means no migration is applied.
+func (m *Migrator) Force(version int) error {
+	return m.migrate.Force(version)
+}
+
+func (m *Migrator) Status() (MigrationStatus, error) {
+	version, dirty, err := m.migrate.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return MigrationStatus{}, err
+	}
+	return MigrationStatus{Version: version, Dirty: dirty, Latest: m.latest}, nil
+}
+
+func (m *Migrator) Close() error {
+	sourceErr, dbErr := m.migrate.Close()
+	return errors.Join(sourceErr, dbErr)
+}
+
+// embeddedLatest caches latestMigration: the embedded files never change.
+var embeddedLatest = sync.OnceValues(latestMigration)
+
+// latestMigration returns the version of the newest embedded migration.
+func latestMigration() (uint, error) {
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+	version, err := src.First()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
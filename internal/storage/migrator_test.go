@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"avito-tech-backend/migrations"
+	"github.com/stretchr/testify/require"
+	"io/fs"
+	"testing"
+)
+
+func TestLatestMigration(t *testing.T) {
+	ups, err := fs.Glob(migrations.FS, "*.up.sql")
+	require.NoError(t, err)
+	downs, err := fs.Glob(migrations.FS, "*.down.sql")
+	require.NoError(t, err)
+	require.Len(t, downs, len(ups), "every migration must be reversible")
+
+	latest, err := latestMigration()
+	require.NoError(t, err)
+	require.EqualValues(t, len(ups), latest, "migrations must be numbered without gaps")
+}
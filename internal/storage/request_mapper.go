@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	sq "github.com/Masterminds/squirrel"
+	"time"
+)
+
+type RequestMapper struct {
+	Storage *Storage
+}
+
+// AddRequests adds counts of user banner requests made on day to the stored
+// ones. Counts of tenants deleted meanwhile are dropped.
+func (m *RequestMapper) AddRequests(ctx context.Context, day time.Time, counts map[BannerPair]int64) error {
+	if len(counts) == 0 {
+		return nil
+	}
+	tenants := make([]string, 0, len(counts))
+	tagIds := make([]int64, 0, len(counts))
+	featureIds := make([]int64, 0, len(counts))
+	values := make([]int64, 0, len(counts))
+	for pair, count := range counts {
+		tenants = append(tenants, pair.Tenant)
+		tagIds = append(tagIds, pair.TagId)
+		featureIds = append(featureIds, pair.FeatureId)
+		values = append(values, count)
+	}
+	rows, err := m.Storage.Database.QuerySq(ctx, sq.Insert("banner_requests").
+		PlaceholderFormat(sq.Dollar).
+		Columns("tenant", "tag_id", "feature_id", "day", "count").
+		Select(sq.Select("r.tenant", "r.tag_id", "r.feature_id").
+			Column("?::date", day).
+			Column("r.count").
+			From("tenants").
+			Join("unnest(?::text[], ?::integer[], ?::integer[], ?::bigint[]) AS r(tenant, tag_id, feature_id, count) "+
+				"ON r.tenant = tenants.name", tenants, tagIds, featureIds, values)).
+		Suffix("ON CONFLICT (tenant, tag_id, feature_id, day) DO UPDATE SET count = banner_requests.count + EXCLUDED.count"))
+	if err != nil {
+		return err
+	}
+	rows.Close()
+	return rows.Err()
+}
+
+// PurgeRequests deletes counts of days before the given one.
+func (m *RequestMapper) PurgeRequests(ctx context.Context, before time.Time) (int64, error) {
+	rows, err := m.Storage.Database.QuerySq(ctx, sq.Delete("banner_requests").
+		PlaceholderFormat(sq.Dollar).
+		Where(sq.Lt{"day": before}).
+		Suffix("RETURNING 1"))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	var purged int64
+	for rows.Next() {
+		purged++
+	}
+	return purged, rows.Err()
+}
@@ -3,18 +3,38 @@ package storage
 import (
 	"avito-tech-backend/internal/pkg/pgdb"
 	"context"
+	"fmt"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	URL string `yaml:"url" env-required:"true"`
+	// AutoMigrate applies pending migrations on boot.
+	AutoMigrate bool `yaml:"autoMigrate"`
+	// Replicas are URLs of read replicas that serve reads outside transactions.
+	Replicas []string `yaml:"replicas"`
+	// ReplicaCheckInterval is how often replicas are pinged; a replica that
+	// does not answer within ReplicaCheckTimeout stops taking reads.
+	ReplicaCheckInterval time.Duration `yaml:"replicaCheckInterval"`
+	ReplicaCheckTimeout  time.Duration `yaml:"replicaCheckTimeout"`
+	// Pool applies to the primary and every replica.
+	Pool pgdb.PoolConfig `yaml:"pool"`
 }
 
 type Storage struct {
 	Config   Config
 	Database *pgdb.Database
 
-	Banners BannerMapper
+	Banners  BannerMapper
+	Features FeatureMapper
+	Tags     TagMapper
+	Drafts   DraftMapper
+
+	Tenants     TenantMapper
+	Impressions ImpressionMapper
+	Requests    RequestMapper
 }
 
 func NewStorage(ctx context.Context, cfg Config) (*Storage, error) {
@@ -22,11 +42,35 @@ func NewStorage(ctx context.Context, cfg Config) (*Storage, error) {
 	storage := &Storage{
 		Config: cfg,
 	}
-	pool, err := pgxpool.Connect(ctx, cfg.URL)
+	pool, err := pgdb.Connect(ctx, cfg.URL, cfg.Pool)
 	if err != nil {
 		return nil, err
 	}
-	storage.Database = pgdb.NewDatabase(pool)
+	replicas := make([]*pgxpool.Pool, 0, len(cfg.Replicas))
+	for i, url := range cfg.Replicas {
+		replica, err := pgdb.Connect(ctx, url, cfg.Pool)
+		if err != nil {
+			pool.Close()
+			for _, r := range replicas {
+				r.Close()
+			}
+			return nil, fmt.Errorf("connect to replica %d: %w", i, err)
+		}
+		replicas = append(replicas, replica)
+	}
+	storage.Database = pgdb.NewDatabase(pool, replicas...)
+	storage.Database.QueryTimeout = cfg.Pool.QueryTimeout
+	go storage.Database.CheckReplicas(ctx, cfg.ReplicaCheckInterval, cfg.ReplicaCheckTimeout)
 	storage.Banners = BannerMapper{Storage: storage}
+	storage.Features = FeatureMapper{Storage: storage}
+	storage.Tags = TagMapper{Storage: storage}
+	storage.Drafts = DraftMapper{Storage: storage}
+	storage.Tenants = TenantMapper{Storage: storage}
+	storage.Impressions = ImpressionMapper{Storage: storage}
+	storage.Requests = RequestMapper{Storage: storage}
 	return storage, nil
 }
+
+func columnList(columns []string) string {
+	return strings.Join(columns, ", ")
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/pkg/access"
+	"context"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+	"time"
+)
+
+var tagColumns = []string{"id", "name", "description", "owner", "is_archived", "created_at", "updated_at", "tenant"}
+
+type TagCreateParams struct {
+	Name        string
+	Description string
+	Owner       string
+}
+
+type TagMapper struct {
+	Storage *Storage
+}
+
+func (m *TagMapper) executeQuery(ctx context.Context, query sq.Sqlizer) ([]entities.Tag, error) {
+	rows, err := m.Storage.Database.QuerySq(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make([]entities.Tag, 0)
+	for rows.Next() {
+		tag, err := toTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+func toTag(rows pgx.Rows) (entities.Tag, error) {
+	var tag entities.Tag
+	err := rows.Scan(&tag.ID, &tag.Name, &tag.Description, &tag.Owner, &tag.IsArchived, &tag.CreatedAt, &tag.UpdatedAt, &tag.Tenant)
+	if err != nil {
+		return entities.Tag{}, err
+	}
+	return tag, nil
+}
+
+func (m *TagMapper) GetAllTags(ctx context.Context, includeArchived bool, scope access.Scope, limit uint64, offset uint64) ([]entities.Tag, error) {
+	q, err := selectScoped(ctx, "tags", tagColumns)
+	if err != nil {
+		return nil, err
+	}
+	q = q.Where(tagAccess(scope)).
+		OrderBy("id").
+		Limit(limit).Offset(offset)
+	if !includeArchived {
+		q = q.Where(sq.Eq{"is_archived": false})
+	}
+	return m.executeQuery(ctx, q)
+}
+
+func (m *TagMapper) FindTagById(ctx context.Context, id int64) (*entities.Tag, error) {
+	q, err := selectScoped(ctx, "tags", tagColumns)
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.executeQuery(ctx, q.Where(sq.Eq{"id": id}))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+func (m *TagMapper) FindTagsByIds(ctx context.Context, ids []int64) ([]entities.Tag, error) {
+	q, err := selectScoped(ctx, "tags", tagColumns)
+	if err != nil {
+		return nil, err
+	}
+	return m.executeQuery(ctx, q.Where(sq.Eq{"id": ids}))
+}
+
+func (m *TagMapper) InsertTag(ctx context.Context, params TagCreateParams) (*entities.Tag, error) {
+	q, err := insertScoped(ctx, "tags",
+		[]string{"name", "description", "owner", "created_at", "updated_at"},
+		params.Name, params.Description, params.Owner, time.Now(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.executeQuery(ctx, q.Suffix("RETURNING "+columnList(tagColumns)))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+func (m *TagMapper) UpdateTagById(ctx context.Context, id int64, params entities.RawTag) (*entities.Tag, error) {
+	q, err := updateScoped(ctx, "tags")
+	if err != nil {
+		return nil, err
+	}
+	if params.Name != nil {
+		q = q.Set("name", *params.Name)
+	}
+	if params.Description != nil {
+		q = q.Set("description", *params.Description)
+	}
+	if params.Owner != nil {
+		q = q.Set("owner", *params.Owner)
+	}
+	if params.IsArchived != nil {
+		q = q.Set("is_archived", *params.IsArchived)
+	}
+	q = q.Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING " + columnList(tagColumns))
+	result, err := m.executeQuery(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+func (m *TagMapper) DeleteTagById(ctx context.Context, id int64) (*entities.Tag, error) {
+	q, err := deleteScoped(ctx, "tags")
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.executeQuery(ctx, q.
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING "+columnList(tagColumns)))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"avito-tech-backend/internal/pkg/tenancy"
+	"context"
+	"errors"
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ErrUnscoped is returned for queries on tenant data made with a context
+// that names no tenant.
+var ErrUnscoped = errors.New("storage: query on tenant data without a tenant scope")
+
+// The builders below start every query on tenant data, so that each query
+// is restricted to the tenant of ctx from the outset and a forgotten filter
+// cannot leak rows across tenants.
+
+func selectScoped(ctx context.Context, table string, columns []string) (sq.SelectBuilder, error) {
+	scope, err := tenantFilter(ctx)
+	if err != nil {
+		return sq.SelectBuilder{}, err
+	}
+	return sq.Select(columns...).From(table).PlaceholderFormat(sq.Dollar).Where(scope), nil
+}
+
+func updateScoped(ctx context.Context, table string) (sq.UpdateBuilder, error) {
+	scope, err := tenantFilter(ctx)
+	if err != nil {
+		return sq.UpdateBuilder{}, err
+	}
+	return sq.Update(table).PlaceholderFormat(sq.Dollar).Where(scope), nil
+}
+
+func deleteScoped(ctx context.Context, table string) (sq.DeleteBuilder, error) {
+	scope, err := tenantFilter(ctx)
+	if err != nil {
+		return sq.DeleteBuilder{}, err
+	}
+	return sq.Delete(table).PlaceholderFormat(sq.Dollar).Where(scope), nil
+}
+
+// insertScoped starts a single-row insert of values into columns, with
+// the tenant of ctx added.
+func insertScoped(ctx context.Context, table string, columns []string, values ...any) (sq.InsertBuilder, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return sq.InsertBuilder{}, err
+	}
+	return sq.Insert(table).
+		PlaceholderFormat(sq.Dollar).
+		Columns(append([]string{"tenant"}, columns...)...).
+		Values(append([]any{tenant}, values...)...), nil
+}
+
+func tenantFilter(ctx context.Context) (sq.Sqlizer, error) {
+	scope, ok := tenancy.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnscoped
+	}
+	if scope.All {
+		return sq.Eq{}, nil
+	}
+	return sq.Eq{"tenant": scope.Tenant}, nil
+}
+
+// tenantOf returns the single tenant new rows are created for.
+func tenantOf(ctx context.Context) (string, error) {
+	scope, ok := tenancy.FromContext(ctx)
+	if !ok || scope.All {
+		return "", ErrUnscoped
+	}
+	return scope.Tenant, nil
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"avito-tech-backend/internal/pkg/tenancy"
+	"context"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestScopedQueriesFailClosed(t *testing.T) {
+	ctx := context.Background()
+	_, err := selectScoped(ctx, "banners", bannerColumns)
+	require.ErrorIs(t, err, ErrUnscoped)
+	_, err = updateScoped(ctx, "banners")
+	require.ErrorIs(t, err, ErrUnscoped)
+	_, err = deleteScoped(ctx, "banners")
+	require.ErrorIs(t, err, ErrUnscoped)
+	_, err = insertScoped(tenancy.WithAllTenants(ctx), "banners", []string{"content"}, "x")
+	require.ErrorIs(t, err, ErrUnscoped, "rows are always created for a single tenant")
+}
+
+func TestScopedQueries(t *testing.T) {
+	ctx := tenancy.WithTenant(context.Background(), "ads")
+
+	q, err := selectScoped(ctx, "banners", []string{"id"})
+	require.NoError(t, err)
+	query, args, err := q.Where(sq.Eq{"id": 1}).ToSql()
+	require.NoError(t, err)
+	require.Equal(t, "SELECT id FROM banners WHERE tenant = $1 AND id = $2", query)
+	require.Equal(t, []any{"ads", 1}, args)
+
+	insert, err := insertScoped(ctx, "tags", []string{"name"}, "promo")
+	require.NoError(t, err)
+	query, args, err = insert.ToSql()
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO tags (tenant,name) VALUES ($1,$2)", query)
+	require.Equal(t, []any{"ads", "promo"}, args)
+
+	all, err := updateScoped(tenancy.WithAllTenants(context.Background()), "banners")
+	require.NoError(t, err)
+	query, _, err = all.Set("is_active", false).ToSql()
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE banners SET is_active = $1 WHERE (1=1)", query)
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"avito-tech-backend/internal/core/entities"
+	"context"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+	"time"
+)
+
+var tenantColumns = []string{"name", "description", "created_at", "updated_at"}
+
+type TenantCreateParams struct {
+	Name        string
+	Description string
+}
+
+// TenantMapper manages the tenants themselves; unlike the other mappers its
+// queries are not scoped to a tenant.
+type TenantMapper struct {
+	Storage *Storage
+}
+
+func (m *TenantMapper) executeQuery(ctx context.Context, query sq.Sqlizer) ([]entities.Tenant, error) {
+	rows, err := m.Storage.Database.QuerySq(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make([]entities.Tenant, 0)
+	for rows.Next() {
+		tenant, err := toTenant(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, tenant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+func toTenant(rows pgx.Rows) (entities.Tenant, error) {
+	var tenant entities.Tenant
+	err := rows.Scan(&tenant.Name, &tenant.Description, &tenant.CreatedAt, &tenant.UpdatedAt)
+	if err != nil {
+		return entities.Tenant{}, err
+	}
+	return tenant, nil
+}
+
+func (m *TenantMapper) GetAllTenants(ctx context.Context, limit uint64, offset uint64) ([]entities.Tenant, error) {
+	return m.executeQuery(ctx, sq.Select(tenantColumns...).From("tenants").
+		PlaceholderFormat(sq.Dollar).
+		OrderBy("name").
+		Limit(limit).Offset(offset))
+}
+
+func (m *TenantMapper) FindTenantByName(ctx context.Context, name string) (*entities.Tenant, error) {
+	result, err := m.executeQuery(ctx, sq.Select(tenantColumns...).From("tenants").
+		PlaceholderFormat(sq.Dollar).
+		Where(sq.Eq{"name": name}))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+// InsertTenant returns nil if a tenant with the name already exists.
+func (m *TenantMapper) InsertTenant(ctx context.Context, params TenantCreateParams) (*entities.Tenant, error) {
+	result, err := m.executeQuery(ctx, sq.Insert("tenants").
+		PlaceholderFormat(sq.Dollar).
+		Columns("name", "description", "created_at", "updated_at").
+		Values(params.Name, params.Description, time.Now(), time.Now()).
+		Suffix("ON CONFLICT (name) DO NOTHING RETURNING "+columnList(tenantColumns)))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+func (m *TenantMapper) UpdateTenantByName(ctx context.Context, params entities.RawTenant) (*entities.Tenant, error) {
+	q := sq.Update("tenants").PlaceholderFormat(sq.Dollar)
+	if params.Description != nil {
+		q = q.Set("description", *params.Description)
+	}
+	q = q.Set("updated_at", time.Now()).
+		Where(sq.Eq{"name": params.Name}).
+		Suffix("RETURNING " + columnList(tenantColumns))
+	result, err := m.executeQuery(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+func (m *TenantMapper) DeleteTenantByName(ctx context.Context, name string) (*entities.Tenant, error) {
+	result, err := m.executeQuery(ctx, sq.Delete("tenants").
+		PlaceholderFormat(sq.Dollar).
+		Where(sq.Eq{"name": name}).
+		Suffix("RETURNING "+columnList(tenantColumns)))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+// IsTenantInUse reports whether any banner, feature or tag belongs to the
+// tenant, trashed banners included.
+func (m *TenantMapper) IsTenantInUse(ctx context.Context, name string) (bool, error) {
+	rows, err := m.Storage.Database.QuerySq(ctx, sq.Select().
+		PlaceholderFormat(sq.Dollar).
+		Column(sq.Expr("EXISTS (SELECT 1 FROM banners WHERE tenant = ?) OR "+
+			"EXISTS (SELECT 1 FROM features WHERE tenant = ?) OR "+
+			"EXISTS (SELECT 1 FROM tags WHERE tenant = ?)", name, name, name)))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	var inUse bool
+	if rows.Next() {
+		if err := rows.Scan(&inUse); err != nil {
+			return false, err
+		}
+	}
+	return inUse, rows.Err()
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS features;
\ No newline at end of file
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS features
+(
+    id          serial          PRIMARY KEY,
+    name        text            NOT NULL,
+    description text            NOT NULL DEFAULT '',
+    owner       text            NOT NULL DEFAULT '',
+    is_archived boolean         NOT NULL DEFAULT false,
+    created_at  timestamptz     NOT NULL DEFAULT now(),
+    updated_at  timestamptz     NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS tags
+(
+    id          serial          PRIMARY KEY,
+    name        text            NOT NULL,
+    description text            NOT NULL DEFAULT '',
+    owner       text            NOT NULL DEFAULT '',
+    is_archived boolean         NOT NULL DEFAULT false,
+    created_at  timestamptz     NOT NULL DEFAULT now(),
+    updated_at  timestamptz     NOT NULL DEFAULT now()
+);
+
+INSERT INTO features (id, name)
+SELECT DISTINCT feature_id, 'feature_' || feature_id FROM banners
+ON CONFLICT DO NOTHING;
+
+INSERT INTO tags (id, name)
+SELECT DISTINCT tag_id, 'tag_' || tag_id FROM banners, unnest(tag_ids) AS tag_id
+ON CONFLICT DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('features', 'id'), GREATEST((SELECT max(id) FROM features), 1));
+SELECT setval(pg_get_serial_sequence('tags', 'id'), GREATEST((SELECT max(id) FROM tags), 1));
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_banners_deleted_at;
+ALTER TABLE banners DROP COLUMN IF EXISTS deleted_at;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE banners ADD COLUMN IF NOT EXISTS deleted_at timestamptz NULL;
+
+CREATE INDEX IF NOT EXISTS idx_banners_deleted_at ON banners (deleted_at) WHERE deleted_at IS NOT NULL;
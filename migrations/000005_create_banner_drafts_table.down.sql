@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS banner_draft_comments;
+DROP TABLE IF EXISTS banner_drafts;
\ No newline at end of file
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS banner_drafts
+(
+    id           serial          PRIMARY KEY,
+    banner_id    int             NOT NULL REFERENCES banners (id) ON DELETE CASCADE,
+    changes      jsonb           NOT NULL,
+    base_version integer         NOT NULL,
+    status       text            NOT NULL,
+    author       text            NOT NULL,
+    reviewer     text            NULL,
+    created_at   timestamptz     NOT NULL,
+    updated_at   timestamptz     NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_banner_drafts_banner_id ON banner_drafts (banner_id);
+
+CREATE TABLE IF NOT EXISTS banner_draft_comments
+(
+    id          serial          PRIMARY KEY,
+    draft_id    int             NOT NULL REFERENCES banner_drafts (id) ON DELETE CASCADE,
+    author      text            NOT NULL,
+    action      text            NOT NULL,
+    body        text            NOT NULL,
+    created_at  timestamptz     NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_banner_draft_comments_draft_id ON banner_draft_comments (draft_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_banners_feature_id_priority;
+ALTER TABLE banners DROP COLUMN IF EXISTS priority;
\ No newline at end of file
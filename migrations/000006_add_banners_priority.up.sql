@@ -0,0 +1,3 @@
+ALTER TABLE banners ADD COLUMN IF NOT EXISTS priority integer NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_banners_feature_id_priority ON banners (feature_id, priority DESC);
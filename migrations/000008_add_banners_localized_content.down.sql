@@ -0,0 +1,2 @@
+ALTER TABLE banners DROP COLUMN IF EXISTS default_locale;
+ALTER TABLE banners DROP COLUMN IF EXISTS localized_content;
\ No newline at end of file
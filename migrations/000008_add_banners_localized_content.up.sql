@@ -0,0 +1,2 @@
+ALTER TABLE banners ADD COLUMN IF NOT EXISTS localized_content jsonb NULL;
+ALTER TABLE banners ADD COLUMN IF NOT EXISTS default_locale text NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS banner_impressions;
+ALTER TABLE banners DROP COLUMN IF EXISTS frequency_cap;
@@ -0,0 +1,12 @@
+ALTER TABLE banners ADD COLUMN IF NOT EXISTS frequency_cap integer NOT NULL DEFAULT 0 CHECK (frequency_cap >= 0);
+
+CREATE TABLE IF NOT EXISTS banner_impressions
+(
+    user_id     text    NOT NULL,
+    banner_id   int     NOT NULL REFERENCES banners (id) ON DELETE CASCADE,
+    day         date    NOT NULL,
+    count       integer NOT NULL,
+    PRIMARY KEY (user_id, banner_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_banner_impressions_day ON banner_impressions (day);
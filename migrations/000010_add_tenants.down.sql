@@ -0,0 +1,5 @@
+ALTER TABLE banner_drafts DROP COLUMN IF EXISTS tenant;
+ALTER TABLE tags DROP COLUMN IF EXISTS tenant;
+ALTER TABLE features DROP COLUMN IF EXISTS tenant;
+ALTER TABLE banners DROP COLUMN IF EXISTS tenant;
+DROP TABLE IF EXISTS tenants;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS tenants
+(
+    name        text            PRIMARY KEY,
+    description text            NOT NULL DEFAULT '',
+    created_at  timestamptz     NOT NULL DEFAULT now(),
+    updated_at  timestamptz     NOT NULL DEFAULT now()
+);
+
+INSERT INTO tenants (name, description) VALUES ('default', 'Data created before tenants were introduced')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE banners ADD COLUMN IF NOT EXISTS tenant text NOT NULL DEFAULT 'default' REFERENCES tenants (name);
+ALTER TABLE features ADD COLUMN IF NOT EXISTS tenant text NOT NULL DEFAULT 'default' REFERENCES tenants (name);
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS tenant text NOT NULL DEFAULT 'default' REFERENCES tenants (name);
+ALTER TABLE banner_drafts ADD COLUMN IF NOT EXISTS tenant text NOT NULL DEFAULT 'default' REFERENCES tenants (name);
+
+CREATE INDEX IF NOT EXISTS idx_banners_tenant_feature_id ON banners (tenant, feature_id);
+CREATE INDEX IF NOT EXISTS idx_features_tenant ON features (tenant);
+CREATE INDEX IF NOT EXISTS idx_tags_tenant ON tags (tenant);
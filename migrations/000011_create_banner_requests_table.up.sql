@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS banner_requests
+(
+    tenant      text    NOT NULL REFERENCES tenants (name) ON DELETE CASCADE,
+    tag_id      int     NOT NULL,
+    feature_id  int     NOT NULL,
+    day         date    NOT NULL,
+    count       bigint  NOT NULL,
+    PRIMARY KEY (tenant, tag_id, feature_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_banner_requests_day ON banner_requests (day);
@@ -0,0 +1,193 @@
+package bannerclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// GetUserBanner returns the banner content the user should see.
+func (c *Client) GetUserBanner(ctx context.Context, q UserBannerQuery) (*UserBanner, error) {
+	entry, err := c.getUserBanners(ctx, "/user_banner", q, nil)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(entry.Body, &body); err != nil {
+		return nil, err
+	}
+	return &UserBanner{Content: body.Content, Locale: entry.Language}, nil
+}
+
+// ListUserBanners returns up to limit banners the user may see, highest
+// priority first. A zero limit uses the server default.
+func (c *Client) ListUserBanners(ctx context.Context, q UserBannerQuery, limit int) ([]UserBannerItem, error) {
+	extra := url.Values{}
+	if limit != 0 {
+		extra.Set("limit", strconv.Itoa(limit))
+	}
+	entry, err := c.getUserBanners(ctx, "/user_banner/list", q, extra)
+	if err != nil {
+		return nil, err
+	}
+	var items []UserBannerItem
+	if err := json.Unmarshal(entry.Body, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// getUserBanners fetches a user endpoint through the cache. A fresh entry is
+// served without a request unless the last revision is asked for; a stale
+// one is revalidated with its ETag.
+func (c *Client) getUserBanners(ctx context.Context, path string, q UserBannerQuery, extra url.Values) (CacheEntry, error) {
+	query := userBannerQuery(q)
+	for key, values := range extra {
+		query[key] = values
+	}
+	header := http.Header{}
+	if q.AcceptLanguage != "" {
+		header.Set("Accept-Language", q.AcceptLanguage)
+	}
+
+	var key string
+	var cached CacheEntry
+	var hit bool
+	if c.cache != nil {
+		// use_last_version shares the entry of the cached request, so that
+		// a fresh answer also refreshes later cached reads.
+		withoutRevision := url.Values{}
+		for k, v := range query {
+			if k != "use_last_version" {
+				withoutRevision[k] = v
+			}
+		}
+		key = c.cacheScope + " " + path + "?" + withoutRevision.Encode() + "#" + q.AcceptLanguage
+		cached, hit = c.cache.Get(key)
+		if hit && !q.UseLastRevision && c.now().Before(cached.Expires) {
+			return cached, nil
+		}
+		if hit && cached.ETag != "" {
+			header.Set("If-None-Match", cached.ETag)
+		}
+	}
+
+	resp, err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, header: header}, nil)
+	if err != nil {
+		return CacheEntry{}, err
+	}
+	now := c.now()
+	if resp.status == http.StatusNotModified && hit {
+		cached.Expires = freshUntil(resp.header, now)
+		c.cache.Set(key, cached)
+		return cached, nil
+	}
+	entry := CacheEntry{
+		Body:     resp.body,
+		ETag:     resp.header.Get("ETag"),
+		Language: resp.header.Get("Content-Language"),
+		Expires:  freshUntil(resp.header, now),
+	}
+	if c.cache != nil && storable(resp.header) {
+		c.cache.Set(key, entry)
+	}
+	return entry, nil
+}
+
+func userBannerQuery(q UserBannerQuery) url.Values {
+	query := url.Values{}
+	query.Set("tag_id", strconv.FormatInt(q.TagId, 10))
+	query.Set("feature_id", strconv.FormatInt(q.FeatureId, 10))
+	if q.UseLastRevision {
+		query.Set("use_last_version", "true")
+	}
+	optional := map[string]string{
+		"user_id":     q.UserId,
+		"platform":    q.Platform,
+		"app_version": q.AppVersion,
+		"locale":      q.Locale,
+		"country":     q.Country,
+	}
+	for key, value := range optional {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	return query
+}
+
+// ListBanners returns banners for admins.
+func (c *Client) ListBanners(ctx context.Context, filter BannerFilter) ([]Banner, error) {
+	query := pageQuery(url.Values{}, Page{Limit: filter.Limit, Offset: filter.Offset})
+	if filter.TagId != 0 {
+		query.Set("tag_id", strconv.FormatInt(filter.TagId, 10))
+	}
+	if filter.FeatureId != 0 {
+		query.Set("feature_id", strconv.FormatInt(filter.FeatureId, 10))
+	}
+	if filter.EmbedNames {
+		query.Set("embed_names", "true")
+	}
+	var banners []Banner
+	_, err := c.do(ctx, request{method: http.MethodGet, path: "/banner", query: query}, &banners)
+	return banners, err
+}
+
+// GetBanner returns a banner including its Version.
+func (c *Client) GetBanner(ctx context.Context, id int64) (*Banner, error) {
+	var banner Banner
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: idPath("/banner/%d", id)}, &banner); err != nil {
+		return nil, err
+	}
+	return &banner, nil
+}
+
+// CreateBanner creates a banner and returns its id.
+func (c *Client) CreateBanner(ctx context.Context, banner Banner) (int64, error) {
+	resp, err := c.do(ctx, request{method: http.MethodPost, path: "/banner", body: banner}, nil)
+	if err != nil {
+		return 0, err
+	}
+	return parseCreatedId(resp.body, "banner_id")
+}
+
+// UpdateBanner applies the non-nil fields of update. With ExpectedVersion
+// set the update only succeeds if the banner was not modified since, and
+// fails with ErrPreconditionFailed otherwise. It returns the new ETag.
+func (c *Client) UpdateBanner(ctx context.Context, id int64, update BannerUpdate) (string, error) {
+	header := http.Header{}
+	if update.ExpectedVersion != nil {
+		header.Set("If-Match", bannerETag(id, *update.ExpectedVersion))
+	}
+	resp, err := c.do(ctx, request{method: http.MethodPatch, path: idPath("/banner/%d", id), header: header, body: update}, nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.header.Get("ETag"), nil
+}
+
+// DeleteBanner moves a banner to the trash.
+func (c *Client) DeleteBanner(ctx context.Context, id int64) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: idPath("/banner/%d", id)}, nil)
+	return err
+}
+
+// ListDeletedBanners returns banners in the trash.
+func (c *Client) ListDeletedBanners(ctx context.Context, page Page) ([]Banner, error) {
+	var banners []Banner
+	_, err := c.do(ctx, request{method: http.MethodGet, path: "/banner/trash", query: pageQuery(url.Values{}, page)}, &banners)
+	return banners, err
+}
+
+// RestoreBanner takes a banner out of the trash.
+func (c *Client) RestoreBanner(ctx context.Context, id int64) (*Banner, error) {
+	var banner Banner
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: idPath("/banner/%d/restore", id)}, &banner); err != nil {
+		return nil, err
+	}
+	return &banner, nil
+}
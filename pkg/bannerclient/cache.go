@@ -0,0 +1,113 @@
+package bannerclient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a user banner response kept for reuse.
+type CacheEntry struct {
+	Body     []byte
+	ETag     string
+	Language string
+	// Expires is when the entry stops being fresh. Stale entries are still
+	// useful to revalidate with If-None-Match.
+	Expires time.Time
+}
+
+// Cache stores user banner responses. Implementations must be safe for
+// concurrent use. One Cache may be shared by clients of different tokens:
+// their entries are kept apart.
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+}
+
+var _ Cache = (*MemoryCache)(nil)
+
+// MemoryCache is a process-local Cache holding up to a fixed number of
+// entries. When full, expired entries are dropped first, then arbitrary ones.
+type MemoryCache struct {
+	mu         sync.Mutex
+	entries    map[string]CacheEntry
+	maxEntries int
+	now        func() time.Time
+}
+
+// NewMemoryCache returns new *MemoryCache.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries < 1 {
+		maxEntries = 1
+	}
+	return &MemoryCache{
+		entries:    make(map[string]CacheEntry),
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+func (c *MemoryCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+func (c *MemoryCache) Set(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		c.evict()
+	}
+	c.entries[key] = entry
+}
+
+func (c *MemoryCache) evict() {
+	now := c.now()
+	for key, entry := range c.entries {
+		if !now.Before(entry.Expires) {
+			delete(c.entries, key)
+		}
+	}
+	for key := range c.entries {
+		if len(c.entries) < c.maxEntries {
+			return
+		}
+		delete(c.entries, key)
+	}
+}
+
+// freshUntil reads how long a response may be reused from Cache-Control.
+// Responses without max-age, such as those for the last revision, are
+// stale right away and are always revalidated.
+func freshUntil(header http.Header, now time.Time) time.Time {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "no-cache" || directive == "no-store" {
+			return now
+		}
+		if value, ok := strings.CutPrefix(directive, "max-age="); ok {
+			if seconds, err := strconv.Atoi(value); err == nil {
+				return now.Add(time.Duration(seconds) * time.Second)
+			}
+		}
+	}
+	return now
+}
+
+func storable(header http.Header) bool {
+	return !strings.Contains(header.Get("Cache-Control"), "no-store")
+}
+
+// cacheScope identifies the service and token a client caches responses for,
+// without putting the token itself into keys.
+func cacheScope(baseURL *url.URL, token string) string {
+	sum := sha256.Sum256([]byte(baseURL.String() + "\x00" + token))
+	return hex.EncodeToString(sum[:8])
+}
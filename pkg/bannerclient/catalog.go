@@ -0,0 +1,89 @@
+package bannerclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+func catalogQuery(filter CatalogFilter) url.Values {
+	query := pageQuery(url.Values{}, filter.Page)
+	if filter.IncludeArchived {
+		query.Set("include_archived", "true")
+	}
+	return query
+}
+
+func (c *Client) ListFeatures(ctx context.Context, filter CatalogFilter) ([]Feature, error) {
+	var features []Feature
+	_, err := c.do(ctx, request{method: http.MethodGet, path: "/feature", query: catalogQuery(filter)}, &features)
+	return features, err
+}
+
+func (c *Client) GetFeature(ctx context.Context, id int64) (*Feature, error) {
+	var feature Feature
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: idPath("/feature/%d", id)}, &feature); err != nil {
+		return nil, err
+	}
+	return &feature, nil
+}
+
+// CreateFeature registers a feature and returns its id.
+func (c *Client) CreateFeature(ctx context.Context, feature Feature) (int64, error) {
+	resp, err := c.do(ctx, request{method: http.MethodPost, path: "/feature", body: feature}, nil)
+	if err != nil {
+		return 0, err
+	}
+	return parseCreatedId(resp.body, "feature_id")
+}
+
+func (c *Client) UpdateFeature(ctx context.Context, id int64, update FeatureUpdate) (*Feature, error) {
+	var feature Feature
+	if _, err := c.do(ctx, request{method: http.MethodPatch, path: idPath("/feature/%d", id), body: update}, &feature); err != nil {
+		return nil, err
+	}
+	return &feature, nil
+}
+
+// DeleteFeature removes a feature no banner refers to.
+func (c *Client) DeleteFeature(ctx context.Context, id int64) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: idPath("/feature/%d", id)}, nil)
+	return err
+}
+
+func (c *Client) ListTags(ctx context.Context, filter CatalogFilter) ([]Tag, error) {
+	var tags []Tag
+	_, err := c.do(ctx, request{method: http.MethodGet, path: "/tag", query: catalogQuery(filter)}, &tags)
+	return tags, err
+}
+
+func (c *Client) GetTag(ctx context.Context, id int64) (*Tag, error) {
+	var tag Tag
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: idPath("/tag/%d", id)}, &tag); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// CreateTag registers a tag and returns its id.
+func (c *Client) CreateTag(ctx context.Context, tag Tag) (int64, error) {
+	resp, err := c.do(ctx, request{method: http.MethodPost, path: "/tag", body: tag}, nil)
+	if err != nil {
+		return 0, err
+	}
+	return parseCreatedId(resp.body, "tag_id")
+}
+
+func (c *Client) UpdateTag(ctx context.Context, id int64, update TagUpdate) (*Tag, error) {
+	var tag Tag
+	if _, err := c.do(ctx, request{method: http.MethodPatch, path: idPath("/tag/%d", id), body: update}, &tag); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// DeleteTag removes a tag no banner refers to.
+func (c *Client) DeleteTag(ctx context.Context, id int64) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: idPath("/tag/%d", id)}, nil)
+	return err
+}
@@ -0,0 +1,285 @@
+// Package bannerclient is a Go client for the banner service API.
+//
+//	client, err := bannerclient.New("http://banners:8080", token,
+//		bannerclient.WithCache(bannerclient.NewMemoryCache(10_000)))
+//	banner, err := client.GetUserBanner(ctx, bannerclient.UserBannerQuery{TagId: 1, FeatureId: 2})
+//	if errors.Is(err, bannerclient.ErrNotFound) {
+//		// nothing to show
+//	}
+package bannerclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/avast/retry-go/v4"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAttempts   = 3
+	defaultMinBackoff = 100 * time.Millisecond
+	defaultMaxBackoff = 2 * time.Second
+	defaultTimeout    = 10 * time.Second
+)
+
+type Option func(c *Client)
+
+// WithHTTPClient sets the client used to send requests.
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) {
+		c.http = h
+	}
+}
+
+// WithRetry sets how many times a request is attempted and the bounds of
+// the exponential backoff between attempts. One attempt disables retries.
+func WithRetry(attempts uint, minBackoff time.Duration, maxBackoff time.Duration) Option {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return func(c *Client) {
+		c.attempts = attempts
+		c.minBackoff = minBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// WithCache keeps user banners for as long as the server allows.
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// Client calls the banner service on behalf of the owner of one token. It
+// is safe for concurrent use.
+//
+// Failed requests are retried with exponential backoff when the server asks
+// to slow down and, for idempotent methods, on network errors and
+// 502/503/504 responses.
+type Client struct {
+	baseURL *url.URL
+	token   string
+	http    *http.Client
+	cache   Cache
+	// cacheScope prefixes cache keys, as the cache may be shared.
+	cacheScope string
+
+	attempts   uint
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	now func() time.Time
+}
+
+// New returns new *Client for the service at baseURL.
+func New(baseURL string, token string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("banner service url: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("banner service url %q must be absolute", baseURL)
+	}
+	c := &Client{
+		baseURL:    u,
+		token:      token,
+		http:       &http.Client{Timeout: defaultTimeout},
+		attempts:   defaultAttempts,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+		now:        time.Now,
+		cacheScope: cacheScope(u, token),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   any
+}
+
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// do sends req, retrying as configured, and decodes a successful response
+// body into out unless out is nil. Error responses become *APIError; 304
+// Not Modified is returned as is.
+func (c *Client) do(ctx context.Context, req request, out any) (*response, error) {
+	var payload []byte
+	if req.body != nil {
+		var err error
+		payload, err = json.Marshal(req.body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	resp, err := retry.DoWithData(func() (*response, error) {
+		return c.send(ctx, req, payload)
+	},
+		retry.Context(ctx),
+		retry.Attempts(c.attempts),
+		retry.Delay(c.minBackoff),
+		retry.MaxJitter(c.minBackoff),
+		retry.MaxDelay(c.maxBackoff),
+		retry.DelayType(retryDelay),
+		retry.RetryIf(retryable(req.method)),
+		retry.LastErrorOnly(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if out != nil && resp.status != http.StatusNotModified && len(resp.body) != 0 {
+		if err := json.Unmarshal(resp.body, out); err != nil {
+			return nil, fmt.Errorf("decode %s %s response: %w", req.method, req.path, err)
+		}
+	}
+	return resp, nil
+}
+
+func (c *Client) send(ctx context.Context, req request, payload []byte) (*response, error) {
+	u := c.baseURL.JoinPath(req.path)
+	if len(req.query) != 0 {
+		u.RawQuery = req.query.Encode()
+	}
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range req.header {
+		httpReq.Header[key] = values
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if payload != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		httpReq.Header.Set("token", c.token)
+	}
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp := &response{status: httpResp.StatusCode, header: httpResp.Header, body: respBody}
+	if resp.status >= http.StatusBadRequest {
+		return nil, apiError(resp)
+	}
+	return resp, nil
+}
+
+func apiError(resp *response) *APIError {
+	var body struct {
+		Error     string         `json:"error"`
+		Code      string         `json:"code"`
+		Details   map[string]any `json:"details"`
+		RequestID string         `json:"request_id"`
+	}
+	_ = json.Unmarshal(resp.body, &body)
+	e := &APIError{
+		StatusCode: resp.status,
+		Code:       body.Code,
+		Message:    body.Error,
+		Details:    body.Details,
+		RequestID:  body.RequestID,
+	}
+	if e.RequestID == "" {
+		e.RequestID = resp.header.Get("X-Request-ID")
+	}
+	if seconds, err := strconv.Atoi(resp.header.Get("Retry-After")); err == nil && seconds > 0 {
+		e.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return e
+}
+
+// retryable decides which failures are worth another attempt. Rate limited
+// requests were rejected before being handled, so they are safe to repeat
+// for any method; other failures may have had an effect on the server.
+func retryable(method string) retry.RetryIfFunc {
+	idempotent := method == http.MethodGet || method == http.MethodHead || method == http.MethodDelete
+	return func(err error) bool {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			return idempotent
+		}
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests:
+			return true
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return idempotent
+		default:
+			return false
+		}
+	}
+}
+
+// retryDelay honours Retry-After and otherwise backs off exponentially with
+// jitter.
+func retryDelay(n uint, err error, config *retry.Config) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+	return retry.CombineDelay(retry.BackOffDelay, retry.RandomDelay)(n, err, config)
+}
+
+func idPath(format string, id int64) string {
+	return fmt.Sprintf(format, id)
+}
+
+func pageQuery(query url.Values, page Page) url.Values {
+	if page.Limit != 0 {
+		query.Set("limit", strconv.FormatUint(page.Limit, 10))
+	}
+	if page.Offset != 0 {
+		query.Set("offset", strconv.FormatUint(page.Offset, 10))
+	}
+	return query
+}
+
+// bannerETag formats the ETag the service uses for a banner revision.
+func bannerETag(id int64, version int64) string {
+	return `"` + strconv.FormatInt(id, 10) + "-" + strconv.FormatInt(version, 10) + `"`
+}
+
+// parseCreatedId reads the id of a created resource from {"<key>": id}.
+func parseCreatedId(body []byte, key string) (int64, error) {
+	var created map[string]int64
+	if err := json.Unmarshal(body, &created); err != nil {
+		return 0, err
+	}
+	id, ok := created[key]
+	if !ok {
+		return 0, fmt.Errorf("response has no %s: %s", key, strings.TrimSpace(string(body)))
+	}
+	return id, nil
+}
@@ -0,0 +1,132 @@
+package bannerclient
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	opts = append([]Option{WithRetry(3, time.Millisecond, 5*time.Millisecond)}, opts...)
+	client, err := New(server.URL, "admin_token", opts...)
+	require.NoError(t, err)
+	return client
+}
+
+func TestClientErrors(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "admin_token", r.Header.Get("token"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"banner not found","code":"banner_not_found","request_id":"r1"}`))
+	})
+	_, err := client.GetBanner(context.Background(), 7)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, err, &APIError{StatusCode: http.StatusNotFound, Code: "banner_not_found"})
+	require.NotErrorIs(t, err, ErrConflict)
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, "r1", apiErr.RequestID)
+}
+
+func TestClientRetries(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"banner_id":7,"version":2}`))
+	})
+	banner, err := client.GetBanner(context.Background(), 7)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, banner.Version)
+	require.EqualValues(t, 3, calls.Load())
+
+	calls.Store(0)
+	_, err = client.CreateBanner(context.Background(), Banner{})
+	require.ErrorIs(t, err, ErrUnavailable)
+	require.EqualValues(t, 1, calls.Load(), "non-idempotent requests are not retried")
+}
+
+func TestClientRetriesRateLimited(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"banner_id":9}`))
+	})
+	id, err := client.CreateBanner(context.Background(), Banner{})
+	require.NoError(t, err)
+	require.EqualValues(t, 9, id)
+	require.EqualValues(t, 2, calls.Load())
+}
+
+func TestClientCache(t *testing.T) {
+	var calls, revalidations atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("ETag", `"1-1"`)
+		if r.URL.Query().Get("use_last_version") == "true" {
+			w.Header().Set("Cache-Control", "no-cache")
+		} else {
+			w.Header().Set("Cache-Control", "private, max-age=60")
+		}
+		if r.Header.Get("If-None-Match") == `"1-1"` {
+			revalidations.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Language", "en")
+		_, _ = w.Write([]byte(`{"content":"hello"}`))
+	}, WithCache(NewMemoryCache(10)))
+
+	ctx := context.Background()
+	query := UserBannerQuery{TagId: 1, FeatureId: 2}
+	for i := 0; i < 3; i++ {
+		banner, err := client.GetUserBanner(ctx, query)
+		require.NoError(t, err)
+		require.Equal(t, UserBanner{Content: "hello", Locale: "en"}, *banner)
+	}
+	require.EqualValues(t, 1, calls.Load(), "fresh entries are served locally")
+
+	query.UseLastRevision = true
+	banner, err := client.GetUserBanner(ctx, query)
+	require.NoError(t, err)
+	require.Equal(t, "hello", banner.Content)
+	require.EqualValues(t, 2, calls.Load(), "the last revision always reaches the server")
+	require.EqualValues(t, 1, revalidations.Load())
+}
+
+func TestClientSharedCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "private, max-age=60")
+		_, _ = w.Write([]byte(`{"content":"` + r.Header.Get("token") + `"}`))
+	}))
+	t.Cleanup(server.Close)
+	cache := NewMemoryCache(10)
+	alice, err := New(server.URL, "alice", WithCache(cache))
+	require.NoError(t, err)
+	bob, err := New(server.URL, "bob", WithCache(cache))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	query := UserBannerQuery{TagId: 1, FeatureId: 2}
+	banner, err := alice.GetUserBanner(ctx, query)
+	require.NoError(t, err)
+	require.Equal(t, "alice", banner.Content)
+	banner, err = bob.GetUserBanner(ctx, query)
+	require.NoError(t, err)
+	require.Equal(t, "bob", banner.Content, "clients must not see each other's entries")
+}
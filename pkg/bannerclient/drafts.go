@@ -0,0 +1,53 @@
+package bannerclient
+
+import (
+	"context"
+	"net/http"
+)
+
+type commentRequest struct {
+	Comment string `json:"comment,omitempty"`
+}
+
+// ListDrafts returns the drafts proposed for a banner.
+func (c *Client) ListDrafts(ctx context.Context, bannerId int64) ([]Draft, error) {
+	var drafts []Draft
+	_, err := c.do(ctx, request{method: http.MethodGet, path: idPath("/banner/%d/drafts", bannerId)}, &drafts)
+	return drafts, err
+}
+
+// CreateDraft proposes changes to a banner.
+func (c *Client) CreateDraft(ctx context.Context, bannerId int64, changes BannerUpdate, comment string) (*Draft, error) {
+	body := struct {
+		BannerUpdate
+		commentRequest
+	}{changes, commentRequest{Comment: comment}}
+	var draft Draft
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: idPath("/banner/%d/drafts", bannerId), body: body}, &draft); err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// SubmitDraft sends a draft for review.
+func (c *Client) SubmitDraft(ctx context.Context, draftId int64, comment string) (*Draft, error) {
+	return c.reviewDraft(ctx, "/draft/%d/submit", draftId, comment)
+}
+
+// ApproveDraft applies a pending draft to its banner.
+func (c *Client) ApproveDraft(ctx context.Context, draftId int64, comment string) (*Draft, error) {
+	return c.reviewDraft(ctx, "/draft/%d/approve", draftId, comment)
+}
+
+// RejectDraft declines a pending draft.
+func (c *Client) RejectDraft(ctx context.Context, draftId int64, comment string) (*Draft, error) {
+	return c.reviewDraft(ctx, "/draft/%d/reject", draftId, comment)
+}
+
+func (c *Client) reviewDraft(ctx context.Context, format string, draftId int64, comment string) (*Draft, error) {
+	var draft Draft
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: idPath(format, draftId), body: commentRequest{Comment: comment}}, &draft); err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
@@ -0,0 +1,52 @@
+package bannerclient
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// APIError is an error response of the banner service.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Details    map[string]any
+	RequestID  string
+	// RetryAfter is how long the server asked to wait before retrying.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	if e.Code == "" {
+		return fmt.Sprintf("banner service: %d %s", e.StatusCode, message)
+	}
+	return fmt.Sprintf("banner service: %d %s (%s)", e.StatusCode, message, e.Code)
+}
+
+// Is reports whether target is an *APIError with the same status code and,
+// if target has a Code, the same Code, so that callers can match errors with
+// errors.Is(err, bannerclient.ErrNotFound).
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	if !ok {
+		return false
+	}
+	return e.StatusCode == t.StatusCode && (t.Code == "" || e.Code == t.Code)
+}
+
+var (
+	ErrBadRequest         = &APIError{StatusCode: http.StatusBadRequest}
+	ErrUnauthorized       = &APIError{StatusCode: http.StatusUnauthorized}
+	ErrForbidden          = &APIError{StatusCode: http.StatusForbidden}
+	ErrNotFound           = &APIError{StatusCode: http.StatusNotFound}
+	ErrConflict           = &APIError{StatusCode: http.StatusConflict}
+	ErrPreconditionFailed = &APIError{StatusCode: http.StatusPreconditionFailed}
+	ErrTooManyRequests    = &APIError{StatusCode: http.StatusTooManyRequests}
+	ErrInternal           = &APIError{StatusCode: http.StatusInternalServerError}
+	ErrUnavailable        = &APIError{StatusCode: http.StatusServiceUnavailable}
+)
@@ -0,0 +1,46 @@
+package bannerclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// The tenant endpoints require an operator token.
+
+func (c *Client) ListTenants(ctx context.Context, page Page) ([]Tenant, error) {
+	var tenants []Tenant
+	_, err := c.do(ctx, request{method: http.MethodGet, path: "/tenant", query: pageQuery(url.Values{}, page)}, &tenants)
+	return tenants, err
+}
+
+func (c *Client) GetTenant(ctx context.Context, name string) (*Tenant, error) {
+	var tenant Tenant
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/tenant/" + url.PathEscape(name)}, &tenant); err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// CreateTenant fails with ErrConflict if the name is taken.
+func (c *Client) CreateTenant(ctx context.Context, tenant Tenant) (*Tenant, error) {
+	var created Tenant
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/tenant", body: tenant}, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *Client) UpdateTenant(ctx context.Context, update TenantUpdate) (*Tenant, error) {
+	var tenant Tenant
+	if _, err := c.do(ctx, request{method: http.MethodPatch, path: "/tenant/" + url.PathEscape(update.Name), body: update}, &tenant); err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// DeleteTenant removes a tenant that owns nothing and no token names.
+func (c *Client) DeleteTenant(ctx context.Context, name string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: "/tenant/" + url.PathEscape(name)}, nil)
+	return err
+}
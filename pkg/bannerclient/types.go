@@ -0,0 +1,82 @@
+package bannerclient
+
+import (
+	"avito-tech-backend/internal/core/entities"
+	"avito-tech-backend/internal/core/targeting"
+)
+
+// The entity types are shared with the service, so the client always
+// speaks the same wire format.
+type (
+	Banner       = entities.Banner
+	BannerUpdate = entities.RawBanner
+	NamedRef     = entities.NamedRef
+
+	Feature       = entities.Feature
+	FeatureUpdate = entities.RawFeature
+	Tag           = entities.Tag
+	TagUpdate     = entities.RawTag
+
+	Draft        = entities.BannerDraft
+	DraftComment = entities.DraftComment
+	DraftStatus  = entities.DraftStatus
+
+	Tenant       = entities.Tenant
+	TenantUpdate = entities.RawTenant
+
+	TargetingRules = targeting.Rules
+	VersionRange   = targeting.VersionRange
+)
+
+// UserBannerQuery selects the banner a user sees.
+type UserBannerQuery struct {
+	TagId     int64
+	FeatureId int64
+	// UseLastRevision bypasses both the server and the client cache.
+	UseLastRevision bool
+	// UserId enables frequency capping.
+	UserId string
+
+	Platform   string
+	AppVersion string
+	Locale     string
+	Country    string
+	// AcceptLanguage is sent as the Accept-Language header when Locale is
+	// empty.
+	AcceptLanguage string
+}
+
+// UserBanner is the content of a banner as shown to a user.
+type UserBanner struct {
+	Content string
+	Locale  string
+}
+
+// UserBannerItem is one entry of ListUserBanners.
+type UserBannerItem struct {
+	BannerId int64  `json:"banner_id"`
+	Content  string `json:"content"`
+	Locale   string `json:"locale"`
+	Priority int64  `json:"priority"`
+}
+
+// BannerFilter narrows ListBanners.
+type BannerFilter struct {
+	TagId      int64
+	FeatureId  int64
+	Limit      uint64
+	Offset     uint64
+	EmbedNames bool
+}
+
+// Page selects a window of a listing.
+type Page struct {
+	Limit  uint64
+	Offset uint64
+}
+
+// CatalogFilter narrows ListFeatures and ListTags.
+type CatalogFilter struct {
+	IncludeArchived bool
+	Page
+}
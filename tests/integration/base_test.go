@@ -1,11 +1,11 @@
 package integration
 
 import (
+	"avito-tech-backend/pkg/bannerclient"
 	"context"
-	"github.com/go-resty/resty/v2"
+	"fmt"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
-	"net/http"
 	"os"
 	"testing"
 	"time"
@@ -15,107 +15,114 @@ type ServerTestSuite struct {
 	suite.Suite
 	ctx         context.Context
 	cleanUpTest func()
-	client      *resty.Client
+
+	anonymous *bannerclient.Client
+	user      *bannerclient.Client
+	admin     *bannerclient.Client
 }
 
 func (s *ServerTestSuite) TestBannerPipeline() {
-	type Banner struct {
-		ID        int64      `json:"banner_id"`
-		TagIds    []int64    `json:"tag_ids"`
-		FeatureId int64      `json:"feature_id"`
-		Content   string     `json:"content"`
-		IsActive  bool       `json:"is_active"`
-		CreatedAt *time.Time `json:"created_at"`
-		UpdatedAt *time.Time `json:"updated_at"`
-	}
-	banner := Banner{}
+	t := s.T()
+	suffix := time.Now().Format(time.RFC3339Nano)
 
 	//1
-	r, err := s.client.R().SetBody(Banner{
-		TagIds:    []int64{1, 2},
-		FeatureId: 1,
-		Content:   "Banner 1",
-		IsActive:  true,
-	}).SetResult(&banner).Post("/banner")
-	s.T().Log(string(r.Body()))
-	require.Error(s.T(), err)
-	require.Equalf(s.T(), http.StatusUnauthorized, r.StatusCode(), "Do not authorize, 'token' is empty")
+	_, err := s.anonymous.CreateBanner(s.ctx, bannerclient.Banner{TagIds: []int64{1}, FeatureId: 1, Content: "Banner 1"})
+	require.ErrorIsf(t, err, bannerclient.ErrUnauthorized, "Do not authorize, 'token' is empty")
 
 	//2
-	r, err = s.client.R().SetHeader("token", "user_token").SetBody(Banner{
-		TagIds:    []int64{1, 2},
-		FeatureId: 1,
-		Content:   "Banner 1",
-		IsActive:  true,
-	}).SetResult(&banner).Post("/banner")
-	s.T().Log(string(r.Body()))
-	require.Error(s.T(), err)
-	require.Equalf(s.T(), http.StatusForbidden, r.StatusCode(), "Do not have permission, 'token' is not suitable. POST /banner")
+	_, err = s.user.CreateBanner(s.ctx, bannerclient.Banner{TagIds: []int64{1}, FeatureId: 1, Content: "Banner 1"})
+	require.ErrorIsf(t, err, bannerclient.ErrForbidden, "Do not have permission, 'token' is not suitable. POST /banner")
 
 	//3
-	r, err = s.client.R().SetHeader("token", "user_token").SetBody("").SetResult(&banner).Post("/banner")
-	s.T().Log(string(r.Body()))
-	require.Error(s.T(), err)
-	require.Equalf(s.T(), http.StatusBadRequest, r.StatusCode(), "Do not have required fields. POST /banner")
+	_, err = s.admin.CreateBanner(s.ctx, bannerclient.Banner{})
+	require.ErrorIsf(t, err, bannerclient.ErrBadRequest, "Do not have required fields. POST /banner")
 
 	//4
-	r, err = s.client.R().SetHeader("token", "admin_token").SetBody(Banner{
-		TagIds:    []int64{1, 2},
-		FeatureId: 1,
-		Content:   "Banner 1",
+	featureId, err := s.admin.CreateFeature(s.ctx, bannerclient.Feature{Name: "feature " + suffix})
+	require.NoError(t, err)
+	tagId, err := s.admin.CreateTag(s.ctx, bannerclient.Tag{Name: "tag " + suffix})
+	require.NoError(t, err)
+	bannerId, err := s.admin.CreateBanner(s.ctx, bannerclient.Banner{
+		TagIds:    []int64{tagId},
+		FeatureId: featureId,
+		Content:   `{"title":"Banner 1"}`,
 		IsActive:  true,
-	}).SetResult(&banner).Post("/banner")
-	s.T().Log(string(r.Body()))
-	require.NoError(s.T(), err)
-	require.Equalf(s.T(), http.StatusCreated, r.StatusCode(), "Valid POST /banner")
-	require.NotEmpty(s.T(), banner.ID)
-
-	banner = Banner{}
+	})
+	require.NoErrorf(t, err, "Valid POST /banner")
+	require.NotEmpty(t, bannerId)
 
 	//5
-	r, err = s.client.R().SetHeader("token", "user_token").SetBody(Banner{
-		TagIds:    []int64{1, 2},
-		FeatureId: 2,
-	}).SetResult(&banner).Get("/user_banner")
-	s.T().Log(string(r.Body()))
-	require.Error(s.T(), err)
-	require.Equalf(s.T(), http.StatusNotFound, r.StatusCode(), "Not found banner with query fields. GET /user_banner")
+	_, err = s.user.GetUserBanner(s.ctx, bannerclient.UserBannerQuery{TagId: tagId, FeatureId: featureId + 1_000_000})
+	require.ErrorIsf(t, err, bannerclient.ErrNotFound, "Not found banner with query fields. GET /user_banner")
 
 	//6
-	r, err = s.client.R().SetHeader("token", "user_token").SetBody(Banner{
-		TagIds:    []int64{1, 2},
-		FeatureId: 1,
-	}).SetResult(&banner).Get("/user_banner")
-	s.T().Log(string(r.Body()))
-	require.NoError(s.T(), err)
-	require.Equalf(s.T(), http.StatusOK, r.StatusCode(), "Valid GET /user_banner")
-	require.NotEmpty(s.T(), banner.Content)
+	query := bannerclient.UserBannerQuery{TagId: tagId, FeatureId: featureId, UseLastRevision: true}
+	userBanner, err := s.user.GetUserBanner(s.ctx, query)
+	require.NoErrorf(t, err, "Valid GET /user_banner")
+	require.Equal(t, `{"title":"Banner 1"}`, userBanner.Content)
 
 	//7
-	r, err = s.client.R().SetHeader("token", "admin_token").SetBody(Banner{
-		TagIds:    []int64{1, 2},
-		FeatureId: 1,
-	}).SetResult(&banner).Get("/user_banner")
-	s.T().Log(string(r.Body()))
-	require.NoError(s.T(), err)
-	require.Equalf(s.T(), http.StatusOK, r.StatusCode(), "Valid GET /user_banner")
-	require.NotEmpty(s.T(), banner.Content)
-
+	userBanner, err = s.admin.GetUserBanner(s.ctx, query)
+	require.NoErrorf(t, err, "Valid GET /user_banner")
+	require.NotEmpty(t, userBanner.Content)
+
+	//8
+	banner, err := s.admin.GetBanner(s.ctx, bannerId)
+	require.NoError(t, err)
+	stale := banner.Version - 1
+	content := `{"title":"Banner 1, updated"}`
+	_, err = s.admin.UpdateBanner(s.ctx, bannerId, bannerclient.BannerUpdate{Content: &content, ExpectedVersion: &stale})
+	require.ErrorIsf(t, err, bannerclient.ErrPreconditionFailed, "Stale If-Match. PATCH /banner/%d", bannerId)
+	etag, err := s.admin.UpdateBanner(s.ctx, bannerId, bannerclient.BannerUpdate{Content: &content, ExpectedVersion: &banner.Version})
+	require.NoErrorf(t, err, "Valid PATCH /banner/%d", bannerId)
+	require.Equal(t, fmt.Sprintf(`"%d-%d"`, bannerId, banner.Version+1), etag)
+	userBanner, err = s.user.GetUserBanner(s.ctx, query)
+	require.NoError(t, err)
+	require.Equal(t, content, userBanner.Content)
+
+	//9
+	require.NoError(t, s.admin.DeleteBanner(s.ctx, bannerId))
+	_, err = s.user.GetUserBanner(s.ctx, query)
+	require.ErrorIsf(t, err, bannerclient.ErrNotFound, "Deleted banner. GET /user_banner")
+	restored, err := s.admin.RestoreBanner(s.ctx, bannerId)
+	require.NoError(t, err)
+	require.Nil(t, restored.DeletedAt)
+	_, err = s.user.GetUserBanner(s.ctx, query)
+	require.NoErrorf(t, err, "Restored banner. GET /user_banner")
+
+	//10
+	cached := bannerclient.UserBannerQuery{TagId: tagId, FeatureId: featureId}
+	require.NoError(t, s.admin.DeleteBanner(s.ctx, bannerId))
+	_, err = s.user.GetUserBanner(s.ctx, cached)
+	require.ErrorIsf(t, err, bannerclient.ErrNotFound, "Deleted banner is cached as missing. GET /user_banner")
+	_, err = s.admin.RestoreBanner(s.ctx, bannerId)
+	require.NoError(t, err)
+	userBanner, err = s.user.GetUserBanner(s.ctx, cached)
+	require.NoErrorf(t, err, "Restored banner through the cache. GET /user_banner")
+	require.Equal(t, content, userBanner.Content)
 }
 
 func (s *ServerTestSuite) SetupTest() {
-	time.Sleep(time.Second / 2)
-
-	s.ctx, s.cleanUpTest = context.WithTimeout(context.Background(), time.Second)
+	baseURL := os.Getenv("AVITO_TECH_BACKEND")
+	if baseURL == "" {
+		s.T().Skip("AVITO_TECH_BACKEND is not set")
+	}
 
-	c := resty.New()
-	c.SetBaseURL(os.Getenv("AVITO_TECH_BACKEND"))
+	s.ctx, s.cleanUpTest = context.WithTimeout(context.Background(), 10*time.Second)
 
-	s.client = c
+	var err error
+	s.anonymous, err = bannerclient.New(baseURL, "")
+	require.NoError(s.T(), err)
+	s.user, err = bannerclient.New(baseURL, "user_token")
+	require.NoError(s.T(), err)
+	s.admin, err = bannerclient.New(baseURL, "admin_token")
+	require.NoError(s.T(), err)
 }
 
 func (s *ServerTestSuite) TearDownTest() {
-	s.cleanUpTest()
+	if s.cleanUpTest != nil {
+		s.cleanUpTest()
+	}
 }
 
 func TestBase(t *testing.T) {